@@ -1,13 +1,19 @@
 package autowired
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unicode"
 )
 
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // Scope represents the lifecycle of a dependency
 type Scope int
 
@@ -17,285 +23,1752 @@ const (
 	Request
 )
 
+func (s Scope) String() string {
+	switch s {
+	case Singleton:
+		return "singleton"
+	case Prototype:
+		return "prototype"
+	case Request:
+		return "request"
+	default:
+		return fmt.Sprintf("Scope(%d)", int(s))
+	}
+}
+
+// ContextPolicy controls which context a constructor receives for a context.Context parameter
+type ContextPolicy int
+
+const (
+	// DetachContext gives singletons a context without the deadline and cancellation of the resolving context
+	DetachContext ContextPolicy = iota
+	// InheritContext passes the resolving context through unchanged
+	InheritContext
+)
+
 // Container represents the dependency injection container
 type Container struct {
-	dependencies map[reflect.Type]map[string]*dependencyInfo
-	mu           sync.RWMutex
-	resolving    sync.Map
+	// SingletonContextValues selects which context values survive detaching for singleton constructors.
+	// When nil, detached contexts carry no values.
+	SingletonContextValues func(key interface{}) bool
+
+	// StartContinueOnError makes Start attempt every singleton and aggregate the failures
+	StartContinueOnError bool
+
+	// InitConcurrency bounds how many independent singletons Start constructs in parallel.
+	// Values below 2 start singletons one at a time.
+	InitConcurrency int
+
+	// TrackPrototypes makes scopes take ownership of the prototype instances resolved within them
+	// that have an OnDestroy hook, so the hook runs when the scope is destroyed
+	TrackPrototypes bool
+
+	// Strict makes constructing a singleton fail with ErrCaptiveDependency when one of its constructor
+	// parameters is a prototype or request-scoped registration, which the singleton would pin forever
+	Strict bool
+
+	// RequireScopes makes resolving a request-scoped dependency without a request scope in the context
+	// fail with ErrOutsideScope, instead of falling back to the per-goroutine pool
+	RequireScopes bool
+
+	// RecordTimings records how long each construction takes, for ExportTimings
+	RecordTimings bool
+
+	// SlowThreshold makes the logger warn about every construction taking at least this long,
+	// including the dependencies built on the way. Zero disables the warning.
+	SlowThreshold time.Duration
+
+	// WarnDangling makes Validate and Start warn about constructor parameters whose type is still not
+	// registered, once per missing type. Unlike Validate's errors, the warnings never fail anything.
+	WarnDangling bool
+
+	// IncludeParentInAll makes ResolveAll on a child container continue with the registrations of its parent
+	IncludeParentInAll bool
+
+	// AllowFieldCycles lets singletons depend on each other through autowire-tagged fields.
+	// Each singleton is cached before its tagged fields are injected, so a cycle resolves to
+	// the partially constructed instance instead of failing. Singletons are then constructed
+	// one resolution at a time, so that two resolutions never wait on each other's fields.
+	AllowFieldCycles bool
+
+	dependencies  map[reflect.Type]map[string]*dependencyInfo
+	validators    map[reflect.Type]func(interface{}) error
+	defaultable   map[reflect.Type]bool
+	appended      map[reflect.Type][]string
+	appendMu      sync.Mutex
+	shared        map[string]*sharedSlot
+	bindings      map[reflect.Type]DependencyNode
+	parent        *Container
+	defaultScope  Scope
+	configSource  func(key string) (string, bool)
+	log           atomic.Value
+	loggerFactory atomic.Value
+	timings       []TimingSample
+	timingsMu     sync.Mutex
+	mu            sync.RWMutex
+	early         sync.Map
+	fieldCycleMu  sync.Mutex
+	dangling      sync.Map
+
+	sealed          int32
+	scopesCreated   int64
+	scopesDestroyed int64
+	scopedInstances int64
 }
 
+// Factory builds a dependency, resolving whatever it needs from the container
+type Factory func(ctx context.Context, c *Container) (interface{}, error)
+
 // dependencyInfo holds information about a registered dependency
 type dependencyInfo struct {
+	typ          reflect.Type
+	name         string
 	constructor  reflect.Value
+	factory      Factory
 	scope        Scope
 	instance     atomic.Value
-	initOnce     sync.Once
+	initMu       sync.Mutex
 	hooks        interface{}
 	instancePool sync.Map
+	ctxPolicy    ContextPolicy
+	ttl          time.Duration
+	equal        Equal
+	qualifiers   Qualifiers
+	tags         Tags
+	deprecation  string
+	deprecated   sync.Once
+	timeout      time.Duration
+	retry        RetryPolicy
+	primary      bool
+	phase        int
+	embeds       embedsOption
+	initHook     InitHook
+	regHook      RegistrationHook
+	declared     atomic.Value
+	weakRef      weakOption
+	order        ResolveOrder
+	ready        []func(instance interface{})
+	collections  SliceQualifiers
+}
+
+// Tags labels a registration for lookup with Container.Tagged
+type Tags []string
+
+// Qualifiers maps constructor parameter indexes to the registration name to inject
+type Qualifiers map[int]string
+
+// ConstructTimeout bounds how long constructing the registration may take.
+// A constructor that overruns it keeps running in the background; its result is discarded.
+type ConstructTimeout time.Duration
+
+// InitHook runs after OnInit with access to the container, so a component can register the dependencies it
+// discovers. Registrations it adds apply to subsequent resolutions. Resolving with the ctx it is given keeps
+// the resolution path, so a hook that ends up resolving its own registration fails as a circular dependency
+// instead of recursing forever.
+type InitHook func(ctx context.Context, c *Container, instance interface{}) error
+
+// RegistrationHook runs after OnInit with the registration that built instance, so one hook shared across
+// components can tell them apart by type, name and scope
+type RegistrationHook func(reg Registration, instance interface{}) error
+
+// ResolutionName is filled with the name the constructed registration is resolved under
+type ResolutionName string
+
+var resolutionNameType = reflect.TypeOf(ResolutionName(""))
+
+// SliceQualifiers maps slice parameter indexes of a constructor to the names of the registrations of the
+// element type to collect into them, in order
+type SliceQualifiers map[int][]string
+
+// ResolveOrder lists constructor parameter indexes to resolve first, in the given order.
+// The remaining parameters are resolved afterwards from left to right.
+type ResolveOrder []int
+
+// Equal compares a reconstructed singleton with the instance it replaces. When it reports them equal, TTL expiry
+// and Refresh keep the old instance: the replacement is discarded before its hooks run and the old one is not
+// destroyed.
+type Equal func(old, replacement interface{}) bool
+
+// RetryPolicy retries a failing constructor or factory up to Attempts times in total, waiting Backoff between
+// attempts. Retrying stops early once the resolving context is done.
+type RetryPolicy struct {
+	Attempts int
+	Backoff  time.Duration
 }
 
+// Primary marks the registration that resolves an interface when several registered types implement it
+const Primary primaryOption = true
+
+type primaryOption bool
+
+// phaseOption places a singleton in a startup and teardown phase
+type phaseOption int
+
+// embedsOption binds interfaces embedded by a registration's type to the registration
+type embedsOption []reflect.Type
+
+// ttlOption expires a cached singleton after the given duration
+type ttlOption time.Duration
+
+// adapterOption builds a constructor registration without reflection
+type adapterOption Factory
+
 // LifecycleHooks defines lifecycle hooks for dependencies
 type LifecycleHooks[T any] struct {
 	OnInit    func(T) error
 	OnStart   func(T) error
 	OnDestroy func(T) error
+	// OnWarmup runs from Warmup, once every singleton has started
+	OnWarmup func(T) error
+	// RunOn lists the scopes OnStart runs for, Singleton and Request when empty. A started prototype is
+	// thrown away without anything to stop it, so prototypes only start when listed explicitly.
+	RunOn []Scope
+}
+
+// startsOn reports whether OnStart runs for instances of the given scope
+func (h LifecycleHooks[T]) startsOn(scope Scope) bool {
+	if len(h.RunOn) == 0 {
+		return scope == Singleton || scope == Request
+	}
+	for _, s := range h.RunOn {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
 
 // NewContainer creates a new Container
 func NewContainer() *Container {
 	return &Container{
 		dependencies: make(map[reflect.Type]map[string]*dependencyInfo),
+		validators:   make(map[reflect.Type]func(interface{}) error),
+		defaultable:  make(map[reflect.Type]bool),
+		appended:     make(map[reflect.Type][]string),
 	}
 }
 
 // Register registers a dependency in the container
 func (c *Container) Register(constructor interface{}, options ...interface{}) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	if err := checkConstructor(constructor, options...); err != nil {
+		return err
+	}
+	return c.register(reflect.TypeOf(constructor).Out(0), &dependencyInfo{constructor: reflect.ValueOf(constructor)}, options...)
+}
 
+// checkConstructor reports a constructor that does not return (T) or (T, error), or whose options refer to
+// parameters it does not have
+func checkConstructor(constructor interface{}, options ...interface{}) error {
 	constructorType := reflect.TypeOf(constructor)
-	if constructorType.Kind() != reflect.Func {
-		return fmt.Errorf("constructor must be a function")
+	if constructorType == nil || constructorType.Kind() != reflect.Func {
+		return &ErrInvalidConstructor{Reason: "constructor must be a function"}
 	}
 
-	if constructorType.NumOut() == 0 || (constructorType.NumOut() == 2 && !constructorType.Out(1).Implements(reflect.TypeOf((*error)(nil)).Elem())) {
-		return fmt.Errorf("constructor must return (T) or (T, error)")
+	if constructorType.NumOut() == 0 || (constructorType.NumOut() == 2 && !constructorType.Out(1).Implements(errorType)) {
+		return &ErrInvalidConstructor{Reason: "constructor must return (T) or (T, error)"}
 	}
 
-	typ := constructorType.Out(0)
-	name, scope, hooks := c.processOptions(typ, options...)
+	for _, option := range options {
+		switch v := option.(type) {
+		case Qualifiers:
+			for index := range v {
+				if index < 0 || index >= constructorType.NumIn() {
+					return &ErrInvalidConstructor{Reason: fmt.Sprintf("qualifier for parameter %d is out of range", index)}
+				}
+			}
+		case SliceQualifiers:
+			for index := range v {
+				if index < 0 || index >= constructorType.NumIn() || constructorType.In(index).Kind() != reflect.Slice {
+					return &ErrInvalidConstructor{Reason: fmt.Sprintf("slice qualifier for parameter %d does not match a slice parameter", index)}
+				}
+			}
+		case ResolveOrder:
+			seen := make(map[int]bool, len(v))
+			for _, index := range v {
+				if index < 0 || index >= constructorType.NumIn() || seen[index] {
+					return &ErrInvalidConstructor{Reason: fmt.Sprintf("resolve order entry %d is out of range or repeated", index)}
+				}
+				seen[index] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegisterFactory registers a factory that builds the dependency for typ
+func (c *Container) RegisterFactory(typ reflect.Type, factory Factory, options ...interface{}) error {
+	if factory == nil {
+		return &ErrInvalidConstructor{Reason: "factory must not be nil"}
+	}
+
+	return c.register(typ, &dependencyInfo{factory: factory}, options...)
+}
+
+// RegisterFactoryWithHooks registers a factory for typ with the given scope whose instances run hooks,
+// which must be a LifecycleHooks value
+func (c *Container) RegisterFactoryWithHooks(typ reflect.Type, scope Scope, factory Factory, hooks interface{}, options ...interface{}) error {
+	if _, ok := isLifecycleHooks(hooks); !ok {
+		return &ErrInvalidConstructor{Reason: fmt.Sprintf("hooks must be LifecycleHooks, got %T", hooks)}
+	}
+
+	return c.RegisterFactory(typ, factory, append(options, scope, hooks)...)
+}
+
+func (c *Container) register(typ reflect.Type, info *dependencyInfo, options ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isSealed() {
+		return &ErrSealed{Type: typ}
+	}
+
+	c.processOptions(info, typ, options...)
+
+	if err := c.checkEmbeds(info); err != nil {
+		return err
+	}
 
 	if _, exists := c.dependencies[typ]; !exists {
 		c.dependencies[typ] = make(map[string]*dependencyInfo)
 	}
 
-	c.dependencies[typ][name] = &dependencyInfo{
-		constructor:  reflect.ValueOf(constructor),
-		scope:        scope,
-		hooks:        hooks,
-		instancePool: sync.Map{},
+	c.dependencies[typ][info.name] = info
+
+	for _, iface := range info.embeds {
+		if c.bindings == nil {
+			c.bindings = make(map[reflect.Type]DependencyNode)
+		}
+		c.bindings[iface] = info.node()
 	}
 
 	return nil
 }
 
-// Resolve resolves a dependency from the container
+// checkEmbeds reports an embedded interface of info that is already registered or bound to another
+// registration. The caller holds c.mu.
+func (c *Container) checkEmbeds(info *dependencyInfo) error {
+	for _, iface := range info.embeds {
+		if _, exists := c.dependencies[iface]; exists {
+			return &ErrAlreadyRegistered{Type: iface, Name: getDefaultName(iface)}
+		}
+		if bound, exists := c.bindings[iface]; exists && bound != info.node() {
+			return &ErrAlreadyRegistered{Type: iface, Name: getDefaultName(iface)}
+		}
+	}
+	return nil
+}
+
+// Resolve resolves a dependency from the container.
+// Request-scoped dependencies need a scope and must be resolved with ResolveContext.
 func (c *Container) Resolve(typ reflect.Type, options ...interface{}) (interface{}, error) {
+	info, _, err := c.lookup(typ, c.getResolveName(options...))
+	if err == nil && info.scope == Request {
+		return nil, &ErrOutsideScope{Type: info.typ, Name: info.name}
+	}
+
+	return c.ResolveContext(context.Background(), typ, options...)
+}
+
+// ResolveContext resolves a dependency from the container, passing ctx to constructors
+// that accept a context.Context parameter
+func (c *Container) ResolveContext(ctx context.Context, typ reflect.Type, options ...interface{}) (interface{}, error) {
+	if instance, ok := c.substitute(ctx, typ); ok {
+		return instance, nil
+	}
+
 	name := c.getResolveName(options...)
+	if name == "" {
+		name = nameOverride(ctx, typ)
+	}
+
+	info, deprecation, err := c.lookup(typ, name)
+	if err != nil && c.parent != nil && errors.Is(err, &ErrNotRegistered{}) {
+		return c.parent.ResolveContext(ctx, typ, options...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if deprecation != "" {
+		info.deprecated.Do(func() {
+			c.logger().Warnf("%v is deprecated: %s", info.node(), deprecation)
+		})
+	}
 
 	// Check for circular dependencies
-	if _, resolving := c.resolving.LoadOrStore(typ, true); resolving {
-		return nil, fmt.Errorf("circular dependency detected for type %v", typ)
+	path := resolutionPath(ctx)
+	for i, resolving := range path {
+		if resolving != typ {
+			continue
+		}
+		if instance, ok := c.early.Load(info); ok {
+			return instance, nil
+		}
+		cycle := append(append([]reflect.Type{}, path[i:]...), typ)
+		return nil, &ErrCircularDependency{Path: cycle}
+	}
+
+	return c.resolveDependency(withResolving(withParams(ctx, options), path, typ), info)
+}
+
+// OnReady calls fn with the singleton of typ under name once it is first constructed and cached,
+// or right away if it already is
+func (c *Container) OnReady(typ reflect.Type, name string, fn func(instance interface{})) error {
+	info, _, err := c.lookup(typ, name)
+	if err != nil {
+		return err
+	}
+	if info.scope != Singleton {
+		return fmt.Errorf("cannot wait for %v: only singletons become ready", info.node())
+	}
+
+	info.initMu.Lock()
+	instance, ok := info.cached()
+	if !ok {
+		info.ready = append(info.ready, fn)
+	}
+	info.initMu.Unlock()
+
+	if ok {
+		fn(instance)
+	}
+	return nil
+}
+
+// SetDependencies declares the dependencies of the registration of typ under name, replacing the ones inferred
+// from its constructor in graphs, validation and start ordering. Use it for constructors whose signature does
+// not show what they really depend on, such as factories that resolve from the container themselves.
+func (c *Container) SetDependencies(typ reflect.Type, name string, deps ...DependencyNode) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isSealed() {
+		return &ErrSealed{Type: typ}
 	}
-	defer c.resolving.Delete(typ)
 
-	c.mu.RLock()
 	info, err := c.getDependencyInfo(typ, name)
-	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	info.declared.Store(append([]DependencyNode{}, deps...))
+	return nil
+}
+
+// ResolveWithNames resolves typ, replacing the default name of every type in names with the mapped name
+// throughout the resolution. Explicit names and qualifiers still take precedence. Singletons are cached,
+// so their constructors do not see the overrides unless registered with InheritContext.
+func (c *Container) ResolveWithNames(ctx context.Context, typ reflect.Type, names map[reflect.Type]string) (interface{}, error) {
+	return c.ResolveContext(withNameOverrides(ctx, names), typ)
+}
+
+// Deprecate marks the registration of typ under name as deprecated.
+// The first resolution logs a warning with message, which should point to the replacement.
+func (c *Container) Deprecate(typ reflect.Type, name string, message string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isSealed() {
+		return &ErrSealed{Type: typ}
+	}
 
+	info, err := c.getDependencyInfo(typ, name)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	info.deprecation = message
+	return nil
+}
+
+// ConstructorFor returns the constructor registered for typ under name without invoking it.
+// Factory registrations have no constructor and report false.
+func (c *Container) ConstructorFor(typ reflect.Type, name string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	info, err := c.getDependencyInfo(typ, name)
+	if err != nil || !info.constructor.IsValid() {
+		return nil, false
 	}
 
-	return c.resolveDependency(info)
+	return info.constructor.Interface(), true
 }
 
-func (c *Container) processOptions(typ reflect.Type, options ...interface{}) (string, Scope, interface{}) {
-	var name string
-	scope := Singleton
-	var hooks interface{}
+func (c *Container) processOptions(info *dependencyInfo, typ reflect.Type, options ...interface{}) {
+	info.typ = typ
+	info.scope = Singleton
+	info.ctxPolicy = DetachContext
 
 	for _, option := range options {
 		switch v := option.(type) {
 		case string:
-			name = v
+			info.name = v
 		case Scope:
-			scope = v
+			info.scope = v
+		case ContextPolicy:
+			info.ctxPolicy = v
+		case ttlOption:
+			info.ttl = time.Duration(v)
+		case phaseOption:
+			info.phase = int(v)
+		case primaryOption:
+			info.primary = bool(v)
+		case Equal:
+			info.equal = v
+		case ConstructTimeout:
+			info.timeout = time.Duration(v)
+		case RetryPolicy:
+			info.retry = v
+		case adapterOption:
+			info.factory = Factory(v)
+		case InitHook:
+			info.initHook = v
+		case RegistrationHook:
+			info.regHook = v
+		case weakOption:
+			info.weakRef = v
+		case embedsOption:
+			info.embeds = v
+		case Qualifiers:
+			info.qualifiers = v
+		case ResolveOrder:
+			info.order = v
+		case SliceQualifiers:
+			info.collections = v
+		case Tags:
+			info.tags = append(info.tags, v...)
 		default:
 			if h, ok := isLifecycleHooks(v); ok {
-				hooks = h
+				info.hooks = h
+			}
+		}
+	}
+
+	if info.name == "" {
+		info.name = getDefaultName(typ)
+	}
+}
+
+// SetDefaultLifetime sets the scope RegisterDefault uses when no Scope option is given. Register keeps
+// defaulting to Singleton.
+func (c *Container) SetDefaultLifetime(scope Scope) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.defaultScope = scope
+}
+
+func (c *Container) defaultLifetime() Scope {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.defaultScope
+}
+
+// BindInterface makes the interface iface resolve to the registrations of impl, sharing their cached singletons.
+// impl must already be registered and implement iface. A registration of iface itself takes precedence.
+func (c *Container) BindInterface(iface, impl reflect.Type) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isSealed() {
+		return &ErrSealed{Type: iface}
+	}
+	if iface.Kind() != reflect.Interface {
+		return fmt.Errorf("cannot bind %v: not an interface", iface)
+	}
+	if !impl.Implements(iface) {
+		return fmt.Errorf("cannot bind %v to %v: it does not implement the interface", iface, impl)
+	}
+	if _, exists := c.dependencies[impl]; !exists {
+		return &ErrNotRegistered{Type: impl}
+	}
+
+	if c.bindings == nil {
+		c.bindings = make(map[reflect.Type]DependencyNode)
+	}
+	c.bindings[iface] = DependencyNode{Type: impl}
+	return nil
+}
+
+// implementationOf looks up the registration of the only registered concrete type implementing iface,
+// which is not registered itself
+func (c *Container) implementationOf(iface reflect.Type, name string) (*dependencyInfo, error) {
+	var candidates []reflect.Type
+	for typ := range c.dependencies {
+		if typ.Kind() != reflect.Interface && typ.Implements(iface) {
+			candidates = append(candidates, typ)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, &ErrNotRegistered{Type: iface}
+	case 1:
+		return c.getDependencyInfo(candidates[0], name)
+	}
+
+	var primaries []*dependencyInfo
+	for _, typ := range candidates {
+		for _, info := range c.dependencies[typ] {
+			if info.primary {
+				primaries = append(primaries, info)
+			}
+		}
+	}
+	if len(primaries) == 1 {
+		if name == "" {
+			return primaries[0], nil
+		}
+		return c.getDependencyInfo(primaries[0].typ, name)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].String() < candidates[j].String() })
+	return nil, &ErrAmbiguousImplementation{Interface: iface, Candidates: candidates}
+}
+
+func (c *Container) getResolveName(options ...interface{}) string {
+	for _, option := range options {
+		if n, ok := option.(string); ok {
+			return n
+		}
+	}
+	return ""
+}
+
+func (c *Container) getDependencyInfo(typ reflect.Type, name string) (*dependencyInfo, error) {
+	implementations, exists := c.dependencies[typ]
+	if bound, ok := c.bindings[typ]; !exists && ok {
+		if name == "" {
+			name = bound.Name
+		}
+		return c.getDependencyInfo(bound.Type, name)
+	}
+	if !exists && typ.Kind() == reflect.Interface {
+		return c.implementationOf(typ, name)
+	}
+	if !exists {
+		return nil, &ErrNotRegistered{Type: typ}
+	}
+
+	if name == "" {
+		name = getDefaultName(typ)
+	}
+
+	info, exists := implementations[name]
+	if !exists {
+		return nil, &ErrNotRegistered{Type: typ, Name: name}
+	}
+
+	return info, nil
+}
+
+func (c *Container) resolveDependency(ctx context.Context, info *dependencyInfo) (interface{}, error) {
+	if recorder := recorderOf(ctx); recorder != nil {
+		recorder.resolving(info)
+	}
+	if iso := isolationOf(ctx); iso != nil {
+		return c.resolveIsolatedNode(ctx, iso, info)
+	}
+
+	switch info.scope {
+	case Singleton:
+		if instance, ok := c.shadow(ctx, info.typ); ok {
+			return instance, nil
+		}
+		return c.resolveSingleton(ctx, info)
+	case Prototype:
+		instance, err := c.construct(ctx, info)
+		if err == nil && c.TrackPrototypes && info.onDestroy() != nil {
+			if scope := c.getScope(ctx); scope != nil {
+				if err := c.track(scope, info, instance); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return instance, err
+	case Request:
+		return c.resolveRequest(ctx, info)
+	default:
+		return nil, fmt.Errorf("unknown scope: %v", info.scope)
+	}
+}
+
+func (c *Container) resolveSingleton(ctx context.Context, info *dependencyInfo) (interface{}, error) {
+	if info.ctxPolicy == DetachContext {
+		ctx = c.detachContext(ctx)
+	}
+
+	if instance, ok := info.fresh(); ok {
+		return instance, nil
+	}
+
+	ctx, release := c.holdFieldCycles(ctx)
+	defer release()
+
+	info.initMu.Lock()
+	defer info.initMu.Unlock()
+
+	if instance, ok := info.fresh(); ok {
+		return instance, nil
+	}
+
+	stale, hasStale := info.cached()
+	instance, kept, err := c.reconstruct(ctx, info, stale, hasStale)
+	if err != nil {
+		return nil, err
+	}
+
+	info.store(instance)
+	info.markReady(instance)
+
+	if hasStale && !kept {
+		if onDestroy := info.onDestroy(); onDestroy != nil {
+			if err := onDestroy(stale); err != nil {
+				return nil, err
 			}
 		}
-	}
+	}
+
+	return instance, nil
+}
+
+type fieldCycleKey struct{}
+
+// fieldCycleHold marks the resolution holding fieldCycleMu, and stays in its context once released
+type fieldCycleHold struct {
+	held int32
+}
+
+// holdFieldCycles serializes singleton construction while AllowFieldCycles is set. Otherwise two resolutions
+// could each hold the initMu of one side of a field cycle while injecting the other. Resolutions nested in the
+// one holding the lock, and the goroutines it starts with its context, go ahead without it.
+func (c *Container) holdFieldCycles(ctx context.Context) (context.Context, func()) {
+	if !c.AllowFieldCycles {
+		return ctx, func() {}
+	}
+	if hold, ok := ctx.Value(fieldCycleKey{}).(*fieldCycleHold); ok && atomic.LoadInt32(&hold.held) == 1 {
+		return ctx, func() {}
+	}
+
+	c.fieldCycleMu.Lock()
+	hold := &fieldCycleHold{held: 1}
+	return context.WithValue(ctx, fieldCycleKey{}, hold), func() {
+		atomic.StoreInt32(&hold.held, 0)
+		c.fieldCycleMu.Unlock()
+	}
+}
+
+// onDestroy returns the registration's OnDestroy hook, if any
+func (info *dependencyInfo) onDestroy() func(interface{}) error {
+	if hooks, ok := info.hooks.(LifecycleHooks[interface{}]); ok {
+		return hooks.OnDestroy
+	}
+	return nil
+}
+
+// Refresh stops the cached singleton registered for typ under name, evicts it and constructs a
+// replacement, running its OnInit and OnStart hooks. Dependents that already hold the old instance
+// keep it until they resolve the singleton again.
+func (c *Container) Refresh(ctx context.Context, typ reflect.Type, name string) error {
+	c.mu.RLock()
+	info, err := c.getDependencyInfo(typ, name)
+	c.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+	if info.scope != Singleton {
+		return fmt.Errorf("cannot refresh %v: only singletons can be refreshed", info.node())
+	}
+
+	if info.ctxPolicy == DetachContext {
+		ctx = c.detachContext(ctx)
+	}
+
+	ctx, release := c.holdFieldCycles(ctx)
+	defer release()
+
+	info.initMu.Lock()
+	defer info.initMu.Unlock()
+
+	// Without a comparer the old instance is stopped before its replacement is built; with one it has to stay
+	// alive until the replacement can be compared against it
+	old, hasOld := info.cached()
+	if hasOld && info.equal == nil {
+		info.instance.Store((*singletonInstance)(nil))
+		if onDestroy := info.onDestroy(); onDestroy != nil {
+			if err := onDestroy(old); err != nil {
+				return err
+			}
+		}
+		hasOld = false
+	}
+
+	instance, kept, err := c.reconstruct(withResolving(ctx, resolutionPath(ctx), typ), info, old, hasOld)
+	if err != nil {
+		return err
+	}
+
+	info.store(instance)
+	info.markReady(instance)
+
+	if hasOld && !kept {
+		if onDestroy := info.onDestroy(); onDestroy != nil {
+			return onDestroy(old)
+		}
+	}
+	return nil
+}
+
+// store caches a constructed singleton, starting its TTL
+func (info *dependencyInfo) store(instance interface{}) {
+	box := &singletonInstance{value: instance}
+	if info.weakRef != nil {
+		box = &singletonInstance{ref: info.weakRef(instance)}
+	}
+	if info.ttl > 0 {
+		box.expires = time.Now().Add(info.ttl)
+	}
+	info.instance.Store(box)
+}
+
+// markReady runs the OnReady callbacks waiting for the singleton. The caller holds initMu.
+func (info *dependencyInfo) markReady(instance interface{}) {
+	ready := info.ready
+	info.ready = nil
+	for _, fn := range ready {
+		fn(instance)
+	}
+}
+
+// singletonInstance boxes a cached singleton so that nil instances can be cached too
+type singletonInstance struct {
+	value   interface{}
+	expires time.Time
+	// ref replaces value for weak singletons and reports false once the instance is collected
+	ref func() (interface{}, bool)
+}
+
+func (box *singletonInstance) get() (interface{}, bool) {
+	if box.ref != nil {
+		return box.ref()
+	}
+	return box.value, true
+}
+
+// cached returns the singleton instance if it has been constructed, even if it has expired
+func (info *dependencyInfo) cached() (interface{}, bool) {
+	box, _ := info.instance.Load().(*singletonInstance)
+	if box == nil {
+		return nil, false
+	}
+	return box.get()
+}
+
+// fresh returns the singleton instance if it has been constructed and has not expired
+func (info *dependencyInfo) fresh() (interface{}, bool) {
+	box, _ := info.instance.Load().(*singletonInstance)
+	if box == nil || (!box.expires.IsZero() && time.Now().After(box.expires)) {
+		return nil, false
+	}
+	return box.get()
+}
+
+func (c *Container) resolveRequest(ctx context.Context, info *dependencyInfo) (interface{}, error) {
+	if scope := c.getScope(ctx); scope != nil {
+		return c.resolveScoped(ctx, scope, info)
+	}
+	if c.RequireScopes {
+		return nil, &ErrOutsideScope{Type: info.typ, Name: info.name}
+	}
+
+	key := getGoroutineID()
+	if instance, ok := info.instancePool.Load(key); ok {
+		return instance, nil
+	}
+
+	instance, err := c.construct(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	info.instancePool.Store(key, instance)
+	return instance, nil
+}
+
+func (c *Container) construct(ctx context.Context, info *dependencyInfo) (interface{}, error) {
+	instance, _, err := c.reconstruct(ctx, info, nil, false)
+	return instance, err
+}
+
+// reconstruct constructs a replacement for old, if there is one. When the registration's Equal comparer reports
+// the replacement equal to old, old is returned with kept set and none of the replacement's hooks run.
+func (c *Container) reconstruct(ctx context.Context, info *dependencyInfo, old interface{}, hasOld bool) (instance interface{}, kept bool, err error) {
+	if c.RecordTimings {
+		var done func()
+		ctx, done = c.startTiming(ctx, info)
+		defer done()
+	}
+	if threshold := c.SlowThreshold; threshold > 0 {
+		start := time.Now()
+		defer func() {
+			if elapsed := time.Since(start); elapsed >= threshold {
+				c.logger().Warnf("slow construction of %v took %v", info.node(), elapsed)
+			}
+		}()
+	}
+
+	instance, err = c.build(ctx, info)
+	if err != nil {
+		return nil, false, err
+	}
+	if recorder := recorderOf(ctx); recorder != nil {
+		recorder.constructed()
+	}
+	if hasOld && info.equal != nil && info.equal(old, instance) {
+		return old, true, nil
+	}
+
+	if c.AllowFieldCycles && info.scope == Singleton {
+		c.early.Store(info, instance)
+		defer c.early.Delete(info)
+
+		if err := c.injectTaggedFields(ctx, instance); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// build has already constructed every parameter, hooks included, so a dependency's OnInit always finishes
+	// before the OnInit of anything that depends on it
+	if hooks, ok := info.hooks.(LifecycleHooks[interface{}]); ok {
+		if hooks.OnInit != nil {
+			if err := hooks.OnInit(instance); err != nil {
+				return nil, false, err
+			}
+		}
+		if hooks.OnStart != nil && hooks.startsOn(info.scope) {
+			if err := hooks.OnStart(instance); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	if info.initHook != nil {
+		if err := info.initHook(ctx, c, instance); err != nil {
+			return nil, false, fmt.Errorf("init hook of %v failed: %w", info.node(), err)
+		}
+	}
+	if info.regHook != nil {
+		if err := info.regHook(info.registration(), instance); err != nil {
+			return nil, false, fmt.Errorf("registration hook of %v failed: %w", info.node(), err)
+		}
+	}
+
+	return instance, false, nil
+}
+
+// build constructs an instance of info, retrying failed attempts under its RetryPolicy
+func (c *Container) build(ctx context.Context, info *dependencyInfo) (interface{}, error) {
+	instance, err := c.attempt(ctx, info)
+	for n := 1; n < info.retry.Attempts && info.retryable(err); n++ {
+		wait := time.NewTimer(info.retry.Backoff)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return nil, err
+		case <-callerDone(ctx):
+			wait.Stop()
+			return nil, err
+		case <-wait.C:
+		}
+
+		instance, err = c.attempt(ctx, info)
+	}
+	return instance, err
+}
+
+// retryable reports whether err is a construction failure of info itself rather than of one of its dependencies,
+// which retry under their own policies
+func (info *dependencyInfo) retryable(err error) bool {
+	switch e := err.(type) {
+	case *ErrConstructorFailed:
+		return e.Type == info.typ && !e.dependency
+	case *ErrConstructTimeout:
+		return e.Type == info.typ && e.Name == info.name
+	}
+	return false
+}
+
+// attempt constructs an instance of info once, within its ConstructTimeout
+func (c *Container) attempt(ctx context.Context, info *dependencyInfo) (interface{}, error) {
+	if info.timeout <= 0 {
+		return c.buildInstance(ctx, info)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, info.timeout)
+	defer cancel()
+
+	type result struct {
+		instance interface{}
+		err      error
+	}
+	// Buffered so an abandoned constructor can still deliver its result and exit
+	done := make(chan result, 1)
+	go func() {
+		instance, err := c.buildInstance(ctx, info)
+		done <- result{instance, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.instance, r.err
+	case <-ctx.Done():
+		return nil, &ErrConstructTimeout{Type: info.typ, Name: info.name, Timeout: info.timeout}
+	}
+}
+
+func (c *Container) buildInstance(ctx context.Context, info *dependencyInfo) (interface{}, error) {
+	if info.factory != nil {
+		instance, err := info.factory(ctx, c)
+		if err != nil {
+			// A factory resolves its dependencies itself, so their failures surface through its own error
+			return nil, &ErrConstructorFailed{Type: info.typ, Err: err, dependency: isResolutionFailure(err)}
+		}
+		return instance, nil
+	}
+
+	params, err := c.resolveConstructorParams(ctx, info.constructor.Type(), info)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logArguments(info, params)
+
+	results := info.constructor.Call(params)
+	if len(results) == 2 && !results[1].IsNil() {
+		return nil, &ErrConstructorFailed{Type: info.typ, Err: results[1].Interface().(error)}
+	}
+
+	return results[0].Interface(), nil
+}
+
+// logArguments logs at debug level whether each constructor argument of info is nil, which pinpoints the
+// dependency behind a nil pointer panic in the constructor
+func (c *Container) logArguments(info *dependencyInfo, params []reflect.Value) {
+	logger := c.logger()
+	if _, discard := logger.(nopLogger); discard {
+		return
+	}
+
+	for i, param := range params {
+		state := "set"
+		if isNillable(param.Kind()) && param.IsNil() {
+			state = "nil"
+		}
+		logger.Debugf("constructing %v: argument %d of type %v is %s", info.node(), i, param.Type(), state)
+	}
+}
+
+// resolveConstructorParams resolves the parameters of a constructor or invoked function.
+// info is the registration being constructed, or nil when invoking a plain function.
+func (c *Container) resolveConstructorParams(ctx context.Context, constructorType reflect.Type, info *dependencyInfo) ([]reflect.Value, error) {
+	params := make([]reflect.Value, constructorType.NumIn())
+	for _, i := range info.resolveOrder(constructorType.NumIn()) {
+		paramType := constructorType.In(i)
+		if paramType == contextType {
+			params[i] = reflect.ValueOf(&ctx).Elem()
+			continue
+		}
+		if paramType == resolutionNameType && info != nil {
+			params[i] = reflect.ValueOf(ResolutionName(info.name))
+			continue
+		}
+		if isRegistry(paramType) {
+			params[i] = c.newRegistry(ctx, paramType)
+			continue
+		}
+		if isProvider(paramType) {
+			params[i] = c.newProvider(ctx, paramType)
+			continue
+		}
+		if isParamObject(paramType) {
+			object, err := c.resolveParamObject(ctx, info, paramType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve parameter %d of type %v: %w", i, paramType, err)
+			}
+			params[i] = object
+			continue
+		}
+		if paramType == loggerType {
+			if logger, ok := c.ownerLogger(info); ok {
+				params[i] = reflect.ValueOf(&logger).Elem()
+				continue
+			}
+		}
+
+		if names, ok := info.collection(i); ok {
+			collection, err := c.resolveNamed(ctx, paramType, names)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve parameter %d of type %v: %w", i, paramType, err)
+			}
+			params[i] = collection
+			continue
+		}
+
+		var options []interface{}
+		if name := info.qualifier(i); name != "" {
+			options = append(options, name)
+		}
+
+		if c.Strict && info != nil && info.scope == Singleton {
+			if err := c.checkCaptive(ctx, info, paramType, info.qualifier(i)); err != nil {
+				return nil, err
+			}
+		}
+
+		param, err := c.ResolveContext(ctx, paramType, options...)
+		if err != nil && c.canDefault(paramType, err) {
+			owner := "invoked function"
+			if info != nil {
+				owner = info.node().String()
+			}
+			c.logger().Debugf("%s: parameter %d of type %v is not registered, injecting its zero value", owner, i, paramType)
+			params[i] = reflect.Zero(paramType)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve parameter %d of type %v: %w", i, paramType, err)
+		}
+
+		if param == nil {
+			params[i] = reflect.Zero(paramType)
+		} else {
+			params[i] = reflect.ValueOf(param)
+		}
+	}
+	return params, nil
+}
+
+// canDefault reports whether a constructor parameter of typ that failed with err may be injected as its zero value
+func (c *Container) canDefault(typ reflect.Type, err error) bool {
+	var notRegistered *ErrNotRegistered
+	if !errors.As(err, &notRegistered) || notRegistered.Type != typ || notRegistered.Name != "" {
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.defaultable[typ]
+}
+
+// checkCaptive reports a singleton constructor parameter resolving to a shorter-lived registration
+func (c *Container) checkCaptive(ctx context.Context, info *dependencyInfo, paramType reflect.Type, name string) error {
+	if name == "" {
+		name = nameOverride(ctx, paramType)
+	}
+
+	dep, _, err := c.lookup(paramType, name)
+	if err != nil || dep.scope == Singleton {
+		return nil
+	}
+	return &ErrCaptiveDependency{Singleton: info.node(), Dependency: dep.node(), Scope: dep.scope}
+}
+
+// collection returns the registration names declared for the slice parameter at index
+func (info *dependencyInfo) collection(index int) ([]string, bool) {
+	if info == nil {
+		return nil, false
+	}
+	names, ok := info.collections[index]
+	return names, ok
+}
+
+// resolveNamed builds a slice of sliceType from the registrations of its element type with the given names
+func (c *Container) resolveNamed(ctx context.Context, sliceType reflect.Type, names []string) (reflect.Value, error) {
+	collection := reflect.MakeSlice(sliceType, 0, len(names))
+	for _, name := range names {
+		instance, err := c.ResolveContext(ctx, sliceType.Elem(), name)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if instance == nil {
+			collection = reflect.Append(collection, reflect.Zero(sliceType.Elem()))
+		} else {
+			collection = reflect.Append(collection, reflect.ValueOf(instance))
+		}
+	}
+	return collection, nil
+}
+
+// resolveOrder returns the order in which the n constructor parameters of info are resolved
+func (info *dependencyInfo) resolveOrder(n int) []int {
+	order := make([]int, 0, n)
+	first := make(map[int]bool)
+	if info != nil {
+		for _, index := range info.order {
+			order = append(order, index)
+			first[index] = true
+		}
+	}
+	for i := 0; i < n; i++ {
+		if !first[i] {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// qualifier returns the registration name declared for the constructor parameter at index
+func (info *dependencyInfo) qualifier(index int) string {
+	if info == nil {
+		return ""
+	}
+	return info.qualifiers[index]
+}
+
+// AutoWire automatically injects dependencies into the fields of the given struct
+func (c *Container) AutoWire(target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a pointer to a struct")
+	}
+
+	return c.autowireFields(context.Background(), v.Elem(), false)
+}
+
+// AutoWireNamed fills every field whose autowire tag names a registration from that registration. Unlike
+// AutoWire it does not stop at the first failure: every field it could not fill is reported together.
+func (c *Container) AutoWireNamed(target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a pointer to a struct")
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		name := t.Field(i).Tag.Get("autowire")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		field := v.Field(i)
+		if !field.CanSet() {
+			errs = append(errs, fmt.Errorf("failed to autowire field %s: field is unexported", t.Field(i).Name))
+			continue
+		}
+
+		dependency, err := c.ResolveContext(context.Background(), field.Type(), name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to autowire field %s: %w", t.Field(i).Name, err))
+			continue
+		}
+		if dependency != nil {
+			field.Set(reflect.ValueOf(dependency))
+		}
+	}
+	return joinErrors(errs)
+}
+
+// injectTaggedFields completes a constructed instance by filling its empty autowire-tagged fields
+func (c *Container) injectTaggedFields(ctx context.Context, instance interface{}) error {
+	v := reflect.ValueOf(instance)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return c.autowireFields(ctx, v.Elem(), true)
+}
+
+func (c *Container) autowireFields(ctx context.Context, v reflect.Value, taggedOnly bool) error {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		if key, ok := t.Field(i).Tag.Lookup("config"); ok {
+			// Constructed instances were configured by their constructor
+			if taggedOnly {
+				continue
+			}
+			if err := c.injectConfig(field, key); err != nil {
+				return fmt.Errorf("failed to configure field %s: %w", t.Field(i).Name, err)
+			}
+			continue
+		}
+
+		tag, tagged := t.Field(i).Tag.Lookup("autowire")
+
+		if tag == "-" {
+			continue
+		}
+
+		if taggedOnly && (!tagged || !field.IsZero()) {
+			continue
+		}
+
+		if tag == "" {
+			collection, ok, err := c.resolveCollection(ctx, field.Type())
+			if err != nil {
+				return fmt.Errorf("failed to autowire field %s: %w", t.Field(i).Name, err)
+			}
+			if ok {
+				field.Set(collection)
+				continue
+			}
+		}
+
+		var options []interface{}
+		if tag != "" {
+			options = append(options, tag)
+		}
+
+		dependency, err := c.ResolveContext(ctx, field.Type(), options...)
+		if err != nil {
+			return fmt.Errorf("failed to autowire field %s: %w", t.Field(i).Name, err)
+		}
+
+		if dependency != nil {
+			field.Set(reflect.ValueOf(dependency))
+		}
+
+		if rules, ok := t.Field(i).Tag.Lookup("validate"); ok {
+			if err := c.validateField(field, rules); err != nil {
+				return fmt.Errorf("failed to validate field %s: %w", t.Field(i).Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Start eagerly constructs every singleton, running their OnInit and OnStart hooks.
+// It stops at the first failure unless StartContinueOnError is set, in which case every
+// singleton is attempted and the failures are returned together. Singletons that failed
+// are not cached, so Destroy only tears down the ones that started.
+//
+// Singletons are started level by level in dependency order. Within a level, up to
+// InitConcurrency singletons are constructed in parallel.
+func (c *Container) Start(ctx context.Context) error {
+	if c.WarnDangling {
+		c.warnDangling()
+	}
+
+	var errs []error
+	for _, level := range c.startLevels() {
+		levelErrs := c.startLevel(ctx, level)
+		if len(levelErrs) > 0 && !c.StartContinueOnError {
+			return joinErrors(levelErrs)
+		}
+		errs = append(errs, levelErrs...)
+	}
+
+	return joinErrors(errs)
+}
+
+// Warmup runs the OnWarmup hooks of the started singletons in dependency order.
+// Call it after Start, once the whole system is up, for work such as prefetching caches.
+func (c *Container) Warmup(ctx context.Context) error {
+	for _, level := range c.startLevels() {
+		for _, info := range level {
+			hooks, ok := info.hooks.(LifecycleHooks[interface{}])
+			if !ok || hooks.OnWarmup == nil {
+				continue
+			}
+
+			instance, started := info.cached()
+			if !started {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := hooks.OnWarmup(instance); err != nil {
+				return fmt.Errorf("failed to warm up %v: %w", info.node(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// Warm constructs the registration of typ and everything in its dependency subtree ahead of time, so later
+// resolutions on a hot path find them cached. Dependencies are warmed before their dependents, and declared
+// dependencies that a factory would only resolve lazily are included. Prototypes are not built, since nothing
+// could reuse them, but their own dependencies are. Request-scoped registrations are only warmed when ctx
+// carries a request scope.
+func (c *Container) Warm(ctx context.Context, typ reflect.Type, options ...interface{}) error {
+	root, _, err := c.lookup(typ, c.getResolveName(options...))
+	if err != nil {
+		return err
+	}
+
+	visited := make(map[*dependencyInfo]bool)
+	var warm func(info *dependencyInfo) error
+	warm = func(info *dependencyInfo) error {
+		if visited[info] {
+			return nil
+		}
+		visited[info] = true
+
+		for _, dep := range c.dependenciesOf(info) {
+			target, _, err := c.lookup(dep.Type, dep.Name)
+			if err != nil {
+				// Not every dependency is a registration, such as defaultable parameters
+				continue
+			}
+			if err := warm(target); err != nil {
+				return err
+			}
+		}
+
+		if info.scope == Prototype || (info.scope == Request && c.getScope(ctx) == nil) {
+			return nil
+		}
+		if _, err := c.ResolveContext(ctx, info.typ, info.name); err != nil {
+			return fmt.Errorf("failed to warm %v: %w", info.node(), err)
+		}
+		return nil
+	}
+	return warm(root)
+}
+
+// startLevel constructs independent singletons, returning their failures in level order
+func (c *Container) startLevel(ctx context.Context, level []*dependencyInfo) []error {
+	workers := c.InitConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]error, len(level))
+	var failed int32
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+
+	for i, info := range level {
+		sem <- struct{}{}
+		if atomic.LoadInt32(&failed) != 0 && !c.StartContinueOnError {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, info *dependencyInfo) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if _, err := c.ResolveContext(ctx, info.typ, info.name); err != nil {
+				results[i] = fmt.Errorf("failed to start %v (%s): %w", info.typ, info.name, err)
+				atomic.StoreInt32(&failed, 1)
+			}
+		}(i, info)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// resolveCollection builds a map[string]T keyed by name or a []T sorted by name from every
+// registration of T, empty when there is none. It reports false for other types, for collection types
+// registered directly and for collections of basic kinds such as []byte, which are never built from registrations.
+func (c *Container) resolveCollection(ctx context.Context, typ reflect.Type) (reflect.Value, bool, error) {
+	isMap := typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String
+	if !isMap && typ.Kind() != reflect.Slice || isBasicKind(typ.Elem().Kind()) {
+		return reflect.Value{}, false, nil
+	}
+
+	c.mu.RLock()
+	_, registered := c.dependencies[typ]
+	c.mu.RUnlock()
+	if registered {
+		return reflect.Value{}, false, nil
+	}
+
+	elemType := typ.Elem()
+	names := c.namesFor(elemType)
+
+	var collection reflect.Value
+	if isMap {
+		collection = reflect.MakeMapWithSize(typ, len(names))
+	} else {
+		collection = reflect.MakeSlice(typ, 0, len(names))
+	}
+
+	for _, name := range names {
+		instance, err := c.ResolveContext(ctx, elemType, name)
+		if err != nil {
+			return reflect.Value{}, false, err
+		}
 
-	if name == "" {
-		name = getDefaultName(typ)
+		value := reflect.Zero(elemType)
+		if instance != nil {
+			value = reflect.ValueOf(instance)
+		}
+
+		if isMap {
+			collection.SetMapIndex(reflect.ValueOf(name).Convert(typ.Key()), value)
+		} else {
+			collection = reflect.Append(collection, value)
+		}
 	}
 
-	return name, scope, hooks
+	return collection, true, nil
 }
 
-func (c *Container) getResolveName(options ...interface{}) string {
-	for _, option := range options {
-		if n, ok := option.(string); ok {
-			return n
-		}
-	}
-	return ""
+// isBasicKind reports whether kind is a boolean, numeric or string kind
+func isBasicKind(kind reflect.Kind) bool {
+	return kind >= reflect.Bool && kind <= reflect.Complex128 || kind == reflect.String
 }
 
-func (c *Container) getDependencyInfo(typ reflect.Type, name string) (*dependencyInfo, error) {
-	implementations, exists := c.dependencies[typ]
-	if !exists {
-		return nil, fmt.Errorf("no dependency registered for type %v", typ)
-	}
+// namesFor returns the names registered for typ in sorted order
+func (c *Container) namesFor(typ reflect.Type) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	if name == "" {
-		name = getDefaultName(typ)
+	names := make([]string, 0, len(c.dependencies[typ]))
+	for name := range c.dependencies[typ] {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	info, exists := implementations[name]
-	if !exists {
-		return nil, fmt.Errorf("no dependency named '%s' registered for type %v", name, typ)
+// singletons returns the singleton registrations in a deterministic order
+func (c *Container) singletons() []*dependencyInfo {
+	var infos []*dependencyInfo
+	for _, info := range c.registrations() {
+		if info.scope == Singleton {
+			infos = append(infos, info)
+		}
 	}
-
-	return info, nil
+	return infos
 }
 
-func (c *Container) resolveDependency(info *dependencyInfo) (interface{}, error) {
-	switch info.scope {
-	case Singleton:
-		return c.resolveSingleton(info)
-	case Prototype:
-		return c.construct(info)
-	case Request:
-		return c.resolveRequest(info)
-	default:
-		return nil, fmt.Errorf("unknown scope: %v", info.scope)
+// registrations returns every registration sorted by type and name
+func (c *Container) registrations() []*dependencyInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var infos []*dependencyInfo
+	for _, implementations := range c.dependencies {
+		for _, info := range implementations {
+			infos = append(infos, info)
+		}
 	}
-}
 
-func (c *Container) resolveSingleton(info *dependencyInfo) (interface{}, error) {
-	var err error
-	info.initOnce.Do(func() {
-		var instance interface{}
-		instance, err = c.construct(info)
-		if err == nil {
-			info.instance.Store(instance)
+	sort.Slice(infos, func(i, j int) bool {
+		if ti, tj := infos[i].typ.String(), infos[j].typ.String(); ti != tj {
+			return ti < tj
 		}
+		return infos[i].name < infos[j].name
 	})
+	return infos
+}
 
+// ResolveBatch resolves several roots with the same context and returns them in input order,
+// stopping at the first error. Singletons, and request-scoped dependencies when ctx carries a
+// scope, are constructed once and shared by every root; prototypes stay fresh per injection.
+func (c *Container) ResolveBatch(ctx context.Context, types ...reflect.Type) ([]interface{}, error) {
+	instances := make([]interface{}, len(types))
+	for i, typ := range types {
+		instance, err := c.ResolveContext(ctx, typ)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve batch root %d of type %v: %w", i, typ, err)
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
+// ResolveIsolated resolves typ into a self-contained object graph. Every registration in its subtree is
+// constructed once for this call, whatever its scope, and is neither taken from nor stored in the container's
+// caches. It returns the root along with every instance built for it, keyed by registration.
+func (c *Container) ResolveIsolated(ctx context.Context, typ reflect.Type) (interface{}, map[DependencyNode]interface{}, error) {
+	iso := &isolation{instances: make(map[*dependencyInfo]interface{})}
+	root, err := c.ResolveContext(withIsolation(ctx, iso), typ)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return info.instance.Load(), nil
+	iso.mu.Lock()
+	defer iso.mu.Unlock()
+
+	built := make(map[DependencyNode]interface{}, len(iso.instances))
+	for info, instance := range iso.instances {
+		built[info.node()] = instance
+	}
+	return root, built, nil
 }
 
-func (c *Container) resolveRequest(info *dependencyInfo) (interface{}, error) {
-	key := getGoroutineID()
-	if instance, ok := info.instancePool.Load(key); ok {
+func (c *Container) resolveIsolatedNode(ctx context.Context, iso *isolation, info *dependencyInfo) (interface{}, error) {
+	iso.mu.Lock()
+	instance, ok := iso.instances[info]
+	iso.mu.Unlock()
+	if ok {
 		return instance, nil
 	}
 
-	instance, err := c.construct(info)
+	if info.scope == Singleton && info.ctxPolicy == DetachContext {
+		ctx = c.detachContext(ctx)
+	}
+
+	instance, err := c.construct(ctx, info)
 	if err != nil {
 		return nil, err
 	}
 
-	info.instancePool.Store(key, instance)
+	iso.mu.Lock()
+	defer iso.mu.Unlock()
+
+	if existing, ok := iso.instances[info]; ok {
+		return existing, nil
+	}
+	iso.instances[info] = instance
 	return instance, nil
 }
 
-func (c *Container) construct(info *dependencyInfo) (interface{}, error) {
-	params, err := c.resolveConstructorParams(info.constructor.Type())
+// ResolveTracked resolves a dependency along with a cleanup function that runs its OnDestroy hook.
+// Only prototype instances are owned by the caller; for other scopes the cleanup does nothing,
+// since the container or the request scope tears those instances down.
+func (c *Container) ResolveTracked(ctx context.Context, typ reflect.Type, options ...interface{}) (interface{}, func() error, error) {
+	instance, err := c.ResolveContext(ctx, typ, options...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	results := info.constructor.Call(params)
-	if len(results) == 2 && !results[1].IsNil() {
-		return nil, results[1].Interface().(error)
-	}
+	cleanup := func() error { return nil }
 
-	instance := results[0].Interface()
+	c.mu.RLock()
+	info, err := c.getDependencyInfo(typ, c.getResolveName(options...))
+	c.mu.RUnlock()
 
-	if hooks, ok := info.hooks.(LifecycleHooks[interface{}]); ok {
-		if hooks.OnInit != nil {
-			if err := hooks.OnInit(instance); err != nil {
-				return nil, err
-			}
-		}
-		if hooks.OnStart != nil {
-			if err := hooks.OnStart(instance); err != nil {
-				return nil, err
+	// A scope tracking prototypes already owns the instance
+	tracked := c.TrackPrototypes && c.getScope(ctx) != nil
+
+	if err == nil && info.scope == Prototype && !tracked {
+		if onDestroy := info.onDestroy(); onDestroy != nil {
+			var once sync.Once
+			cleanup = func() error {
+				var err error
+				once.Do(func() { err = onDestroy(instance) })
+				return err
 			}
 		}
 	}
 
-	return instance, nil
+	return instance, cleanup, nil
 }
 
-func (c *Container) resolveConstructorParams(constructorType reflect.Type) ([]reflect.Value, error) {
-	params := make([]reflect.Value, constructorType.NumIn())
-	for i := 0; i < constructorType.NumIn(); i++ {
-		paramType := constructorType.In(i)
-		param, err := c.Resolve(paramType)
-		if err != nil {
-			return nil, fmt.Errorf("failed to resolve parameter %d of type %v: %w", i, paramType, err)
-		}
-		params[i] = reflect.ValueOf(param)
-	}
-	return params, nil
+// Invoke resolves the parameters of fn from the container and calls it.
+// If fn's last result is an error, it is returned.
+func (c *Container) Invoke(ctx context.Context, fn interface{}) error {
+	_, err := c.invoke(ctx, fn)
+	return err
 }
 
-// AutoWire automatically injects dependencies into the fields of the given struct
-func (c *Container) AutoWire(target interface{}) error {
-	v := reflect.ValueOf(target)
-	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
-		return fmt.Errorf("target must be a pointer to a struct")
+func (c *Container) invoke(ctx context.Context, fn interface{}) ([]reflect.Value, error) {
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func || fnValue.IsNil() {
+		return nil, fmt.Errorf("fn must be a function")
 	}
 
-	v = v.Elem()
-	t := v.Type()
-
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		if !field.CanSet() {
-			continue
-		}
-
-		tag := t.Field(i).Tag.Get("autowire")
-
-		if tag == "-" {
-			continue
-		}
-
-		var options []interface{}
-		if tag != "" {
-			options = append(options, tag)
-		}
-
-		dependency, err := c.Resolve(field.Type(), options...)
-		if err != nil {
-			return fmt.Errorf("failed to autowire field %s: %w", t.Field(i).Name, err)
-		}
+	params, err := c.resolveConstructorParams(ctx, fnValue.Type(), nil)
+	if err != nil {
+		return nil, err
+	}
 
-		field.Set(reflect.ValueOf(dependency))
+	results := fnValue.Call(params)
+	if n := len(results); n > 0 && fnValue.Type().Out(n-1) == errorType && !results[n-1].IsNil() {
+		return results, results[n-1].Interface().(error)
 	}
 
-	return nil
+	return results, nil
 }
 
+// Destroy runs the OnDestroy hooks of constructed singletons and returns every hook failure.
+// Singletons are destroyed in the reverse of the order Start builds them: by ascending phase, and within a
+// phase dependents before their dependencies.
 func (c *Container) Destroy() error {
+	return c.Shutdown(context.Background())
+}
+
+// Shutdown is like Destroy but bounded by ctx. Once ctx ends, the hook still running is abandoned, the remaining
+// ones are skipped and an ErrShutdownTimeout names every singleton that did not finish stopping.
+func (c *Container) Shutdown(ctx context.Context) error {
+	levels := c.startLevels()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for _, implementations := range c.dependencies {
-		for _, info := range implementations {
-			if hooks, ok := info.hooks.(LifecycleHooks[interface{}]); ok {
-				if hooks.OnDestroy != nil {
-					instance := info.instance.Load()
-					if instance != nil {
-						if err := hooks.OnDestroy(instance); err != nil {
-							return err
-						}
-					}
-				}
+	var errs []error
+	var pending []DependencyNode
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, info := range levels[i] {
+			onDestroy := info.onDestroy()
+			if onDestroy == nil {
+				continue
+			}
+			instance, ok := info.cached()
+			if !ok {
+				continue
+			}
+
+			if ctx.Err() != nil {
+				pending = append(pending, info.node())
+				continue
+			}
+			if finished, err := stopWithin(ctx, onDestroy, instance); !finished {
+				pending = append(pending, info.node())
+			} else if err != nil {
+				errs = append(errs, err)
 			}
 		}
 	}
-	return nil
+	if len(pending) > 0 {
+		errs = append(errs, &ErrShutdownTimeout{Pending: pending, Err: ctx.Err()})
+	}
+	return joinErrors(errs)
+}
+
+// stopWithin runs onDestroy for instance, giving up when ctx ends first. Without a deadline or cancellation on
+// ctx the hook runs on the calling goroutine.
+func stopWithin(ctx context.Context, onDestroy func(interface{}) error, instance interface{}) (finished bool, err error) {
+	if ctx.Done() == nil {
+		return true, onDestroy(instance)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- onDestroy(instance)
+	}()
+
+	select {
+	case err := <-done:
+		return true, err
+	case <-ctx.Done():
+		return false, nil
+	}
+}
+
+// Close destroys the container so it can be used as an io.Closer
+func (c *Container) Close() error {
+	return c.Destroy()
 }
 
 // ClearRequestScoped clears all request-scoped dependencies
@@ -334,6 +1807,32 @@ func getGoroutineID() uint64 {
 	return uint64(reflect.ValueOf(make(chan int)).Pointer())
 }
 
+// checkFuncResult verifies that fn is a function returning (resultType) or (resultType, error)
+func checkFuncResult(fn interface{}, resultType reflect.Type) error {
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return fmt.Errorf("fn must be a function")
+	}
+	if fnType.NumOut() == 0 || fnType.NumOut() > 2 || !fnType.Out(0).AssignableTo(resultType) ||
+		(fnType.NumOut() == 2 && fnType.Out(1) != errorType) {
+		return fmt.Errorf("fn must return (%v) or (%v, error)", resultType, resultType)
+	}
+	return nil
+}
+
+func isNillable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return true
+	}
+	return false
+}
+
+var scopesType = reflect.TypeOf([]Scope(nil))
+
+// hookNames lists the hook fields of LifecycleHooks
+var hookNames = []string{"OnInit", "OnStart", "OnDestroy", "OnWarmup"}
+
 func isLifecycleHooks(v interface{}) (LifecycleHooks[interface{}], bool) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Struct {
@@ -341,15 +1840,10 @@ func isLifecycleHooks(v interface{}) (LifecycleHooks[interface{}], bool) {
 	}
 
 	rt := rv.Type()
-	if rt.NumField() != 3 {
+	if rt.NumField() != len(hookNames)+1 {
 		return LifecycleHooks[interface{}]{}, false
 	}
-
-	onInitField, hasOnInit := rt.FieldByName("OnInit")
-	onStartField, hasOnStart := rt.FieldByName("OnStart")
-	onDestroyField, hasOnDestroy := rt.FieldByName("OnDestroy")
-
-	if !hasOnInit || !hasOnStart || !hasOnDestroy {
+	if field, ok := rt.FieldByName("RunOn"); !ok || field.Type != scopesType {
 		return LifecycleHooks[interface{}]{}, false
 	}
 
@@ -357,17 +1851,22 @@ func isLifecycleHooks(v interface{}) (LifecycleHooks[interface{}], bool) {
 		return f.Type.Kind() == reflect.Func &&
 			f.Type.NumIn() == 1 &&
 			f.Type.NumOut() == 1 &&
-			f.Type.Out(0) == reflect.TypeOf((*error)(nil)).Elem()
+			f.Type.Out(0) == errorType
 	}
 
-	if !isValidHook(onInitField) || !isValidHook(onStartField) || !isValidHook(onDestroyField) {
-		return LifecycleHooks[interface{}]{}, false
+	for _, name := range hookNames {
+		field, ok := rt.FieldByName(name)
+		if !ok || !isValidHook(field) {
+			return LifecycleHooks[interface{}]{}, false
+		}
 	}
 
 	return LifecycleHooks[interface{}]{
 		OnInit:    convertToInterfaceFunc(rv.FieldByName("OnInit")),
 		OnStart:   convertToInterfaceFunc(rv.FieldByName("OnStart")),
 		OnDestroy: convertToInterfaceFunc(rv.FieldByName("OnDestroy")),
+		OnWarmup:  convertToInterfaceFunc(rv.FieldByName("OnWarmup")),
+		RunOn:     rv.FieldByName("RunOn").Interface().([]Scope),
 	}, true
 }
 
@@ -388,18 +1887,259 @@ func convertToInterfaceFunc(v reflect.Value) func(interface{}) error {
 // Type-safe wrappers
 
 func Register[T any](c *Container, constructor interface{}, options ...interface{}) error {
+	return registerTyped[T](c, constructor, options...)
+}
+
+// registerTyped registers a constructor after checking that it builds a T or a *T
+func registerTyped[T any](c *Container, constructor interface{}, options ...interface{}) error {
+	if err := checkTyped[T](constructor); err != nil {
+		return err
+	}
 	return c.Register(constructor, options...)
 }
 
+// checkTyped reports a constructor that builds neither a T nor a *T
+func checkTyped[T any](constructor interface{}) error {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	// Invalid constructor shapes are reported by checkConstructor
+	constructorType := reflect.TypeOf(constructor)
+	if constructorType != nil && constructorType.Kind() == reflect.Func && constructorType.NumOut() > 0 {
+		out := constructorType.Out(0)
+		if !out.AssignableTo(typ) && !out.AssignableTo(reflect.PtrTo(typ)) {
+			return &ErrInvalidConstructor{Reason: fmt.Sprintf("constructor returns %v, which is not assignable to %v", out, typ)}
+		}
+	}
+	return nil
+}
+
+func RegisterDefault[T any](c *Container, constructor interface{}, options ...interface{}) error {
+	return registerTyped[T](c, constructor, append([]interface{}{c.defaultLifetime()}, options...)...)
+}
+
+func RegisterAppend[T any](c *Container, constructor interface{}, options ...interface{}) error {
+	constructorType := reflect.TypeOf(constructor)
+	if constructorType == nil || constructorType.Kind() != reflect.Func || constructorType.NumOut() == 0 {
+		return &ErrInvalidConstructor{Reason: "constructor must be a function"}
+	}
+	typ := constructorType.Out(0)
+
+	c.appendMu.Lock()
+	defer c.appendMu.Unlock()
+
+	name := fmt.Sprintf("%s#%d", getDefaultName(typ), len(c.appended[typ]))
+	if err := registerTyped[T](c, constructor, append(options, name)...); err != nil {
+		return err
+	}
+
+	c.appended[typ] = append(c.appended[typ], name)
+	return nil
+}
+
+func ResolveAllOrdered[T any](ctx context.Context, c *Container) ([]T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	c.appendMu.Lock()
+	names := append([]string{}, c.appended[typ]...)
+	c.appendMu.Unlock()
+
+	return ResolveSlice[T](ctx, c, names...)
+}
+
+func ResolveIndexed[T any](ctx context.Context, c *Container, i int) (T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	c.appendMu.Lock()
+	names := c.appended[typ]
+	count := len(names)
+	var name string
+	if i >= 0 && i < count {
+		name = names[i]
+	}
+	c.appendMu.Unlock()
+
+	if i < 0 || i >= count {
+		var zero T
+		return zero, &ErrIndexOutOfRange{Type: typ, Index: i, Count: count}
+	}
+
+	instance, err := c.ResolveContext(ctx, typ, name)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return as[T](instance), nil
+}
+
+func RegisterDefaultable[T any](c *Container) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.defaultable[reflect.TypeOf((*T)(nil)).Elem()] = true
+}
+
+func RegisterSingletonFast[T any](c *Container, constructor interface{}, adapter func(*Container, context.Context) (T, error), options ...interface{}) error {
+	if adapter == nil {
+		return &ErrInvalidConstructor{Reason: "adapter must not be nil"}
+	}
+
+	factory := func(ctx context.Context, c *Container) (interface{}, error) {
+		return adapter(c, ctx)
+	}
+	return registerTyped[T](c, constructor, append(options, Singleton, adapterOption(factory))...)
+}
+
+func RegisterQualified[T any](c *Container, constructor interface{}, qualifiers map[int]string, options ...interface{}) error {
+	return registerTyped[T](c, constructor, append(options, Qualifiers(qualifiers))...)
+}
+
+func RegisterSingletonWithPhase[T any](c *Container, constructor interface{}, hooks LifecycleHooks[*T], phase int, options ...interface{}) error {
+	return registerTyped[T](c, constructor, append(options, Singleton, hooks, phaseOption(phase))...)
+}
+
+func RegisterSingletonTTL[T any](c *Container, constructor interface{}, ttl time.Duration, options ...interface{}) error {
+	if ttl <= 0 {
+		return fmt.Errorf("ttl must be positive")
+	}
+	return registerTyped[T](c, constructor, append(options, Singleton, ttlOption(ttl))...)
+}
+
+func RegisterWithEmbedded[T any](c *Container, constructor interface{}, options ...interface{}) error {
+	var embeds embedsOption
+	if constructorType := reflect.TypeOf(constructor); constructorType != nil && constructorType.Kind() == reflect.Func && constructorType.NumOut() > 0 {
+		structType := constructorType.Out(0)
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+		if structType.Kind() == reflect.Struct {
+			for i := 0; i < structType.NumField(); i++ {
+				if field := structType.Field(i); field.Anonymous && field.Type.Kind() == reflect.Interface {
+					embeds = append(embeds, field.Type)
+				}
+			}
+		}
+	}
+
+	// The embedded interfaces are bound to the registration in the same step, so they share its scope and
+	// either all of them are registered or none
+	return registerTyped[T](c, constructor, append(options, embeds)...)
+}
+
+func RegisterFuncSingleton[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), options ...interface{}) error {
+	return registerFunc(c, fn, Singleton, options...)
+}
+
+func RegisterFuncPrototype[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), options ...interface{}) error {
+	return registerFunc(c, fn, Prototype, options...)
+}
+
+func RegisterFuncRequest[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), options ...interface{}) error {
+	return registerFunc(c, fn, Request, options...)
+}
+
+func registerFunc[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), scope Scope, options ...interface{}) error {
+	if fn == nil {
+		return &ErrInvalidConstructor{Reason: "factory must not be nil"}
+	}
+
+	factory := func(ctx context.Context, c *Container) (interface{}, error) {
+		return fn(ctx, c)
+	}
+	return c.RegisterFactory(reflect.TypeOf((*T)(nil)).Elem(), factory, append(options, scope)...)
+}
+
+func RegisterSingletonFactoryWithHooks[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), hooks LifecycleHooks[T], options ...interface{}) error {
+	return registerFunc[T](c, fn, Singleton, append(options, hooks)...)
+}
+
+func RegisterPrototypeFactoryWithHooks[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), hooks LifecycleHooks[T], options ...interface{}) error {
+	return registerFunc[T](c, fn, Prototype, append(options, hooks)...)
+}
+
+func RegisterRequestFactoryWithHooks[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), hooks LifecycleHooks[T], options ...interface{}) error {
+	return registerFunc[T](c, fn, Request, append(options, hooks)...)
+}
+
+func RegisterFactoryFunc[T any](c *Container, fn interface{}, options ...interface{}) error {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if err := checkFuncResult(fn, typ); err != nil {
+		return &ErrInvalidConstructor{Reason: err.Error()}
+	}
+
+	factory := func(ctx context.Context, c *Container) (interface{}, error) {
+		results, err := c.invoke(ctx, fn)
+		if err != nil {
+			return nil, err
+		}
+		return results[0].Interface(), nil
+	}
+	return c.RegisterFactory(typ, factory, options...)
+}
+
 func Resolve[T any](c *Container, options ...interface{}) (T, error) {
 	var t T
 	instance, err := c.Resolve(reflect.TypeOf(&t).Elem(), options...)
 	if err != nil {
 		return t, err
 	}
-	return instance.(T), nil
+	return as[T](instance), nil
+}
+
+func ResolveContext[T any](ctx context.Context, c *Container, options ...interface{}) (T, error) {
+	var t T
+	instance, err := c.ResolveContext(ctx, reflect.TypeOf(&t).Elem(), options...)
+	if err != nil {
+		return t, err
+	}
+	return as[T](instance), nil
+}
+
+func ResolveSlice[T any](ctx context.Context, c *Container, names ...string) ([]T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if len(names) == 0 {
+		names = c.namesFor(typ)
+	}
+
+	instances := make([]T, 0, len(names))
+	for _, name := range names {
+		instance, err := c.ResolveContext(ctx, typ, name)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, as[T](instance))
+	}
+	return instances, nil
+}
+
+// as converts a resolved instance to T, mapping nil to the zero value
+func as[T any](instance interface{}) T {
+	t, _ := instance.(T)
+	return t
+}
+
+func Invoke1[R any](ctx context.Context, c *Container, fn interface{}) (R, error) {
+	var r R
+	resultType := reflect.TypeOf(&r).Elem()
+
+	if err := checkFuncResult(fn, resultType); err != nil {
+		return r, err
+	}
+
+	results, err := c.invoke(ctx, fn)
+	if err != nil {
+		return r, err
+	}
+
+	if result := results[0]; result.IsValid() && !(isNillable(result.Kind()) && result.IsNil()) {
+		r = result.Interface().(R)
+	}
+	return r, nil
 }
 
 func AutoWire[T any](c *Container, target *T) error {
 	return c.AutoWire(target)
 }
+
+func AutoWireNamed[T any](c *Container, target *T) error {
+	return c.AutoWireNamed(target)
+}
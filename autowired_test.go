@@ -1,9 +1,17 @@
 package autowired_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // Simple service for testing
@@ -227,3 +235,1917 @@ func TestErrorHandling(t *testing.T) {
 		t.Error("Expected error from constructor, got nil")
 	}
 }
+
+type Greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct {
+	Service *TestService
+}
+
+func (g *englishGreeter) Greet() string {
+	return "hello " + g.Service.Value
+}
+
+// Test registering a typed factory and resolving it without a cast
+func TestRegisterFuncSingleton(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[TestService](container, NewTestService)
+	if err != nil {
+		t.Fatalf("Failed to register TestService: %v", err)
+	}
+
+	calls := 0
+	err = autowired.RegisterFuncSingleton[Greeter](container, func(ctx context.Context, c *autowired.Container) (Greeter, error) {
+		calls++
+		service, err := autowired.ResolveContext[*TestService](ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		return &englishGreeter{Service: service}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register Greeter factory: %v", err)
+	}
+
+	greeter, err := autowired.Resolve[Greeter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Greeter: %v", err)
+	}
+
+	if greeter.Greet() != "hello default" {
+		t.Errorf("Expected 'hello default', got '%s'", greeter.Greet())
+	}
+
+	_, _ = autowired.Resolve[Greeter](container)
+	if calls != 1 {
+		t.Errorf("Expected singleton factory to run once, ran %d times", calls)
+	}
+}
+
+// Test prototype factories run on every resolution
+func TestRegisterFuncPrototype(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.RegisterFuncPrototype[*TestService](container, func(ctx context.Context, c *autowired.Container) (*TestService, error) {
+		return &TestService{Value: "factory"}, nil
+	}, "fromFactory")
+	if err != nil {
+		t.Fatalf("Failed to register TestService factory: %v", err)
+	}
+
+	first, err := autowired.Resolve[*TestService](container, "fromFactory")
+	if err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+	second, _ := autowired.Resolve[*TestService](container, "fromFactory")
+
+	if first == second {
+		t.Error("Prototype factory instances should be different")
+	}
+	if first.Value != "factory" {
+		t.Errorf("Expected value 'factory', got '%s'", first.Value)
+	}
+}
+
+type CyclicA struct {
+	B *CyclicB `autowire:""`
+}
+
+type CyclicB struct {
+	A *CyclicA `autowire:""`
+}
+
+// Test field cycles are completed after construction when allowed
+func TestAllowFieldCycles(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[CyclicA](container, func() *CyclicA { return &CyclicA{} })
+	if err != nil {
+		t.Fatalf("Failed to register CyclicA: %v", err)
+	}
+	err = autowired.Register[CyclicB](container, func() *CyclicB { return &CyclicB{} })
+	if err != nil {
+		t.Fatalf("Failed to register CyclicB: %v", err)
+	}
+
+	a, err := autowired.Resolve[*CyclicA](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve CyclicA: %v", err)
+	}
+	if a.B != nil {
+		t.Error("Tagged fields should not be injected unless AllowFieldCycles is set")
+	}
+
+	container = autowired.NewContainer()
+	container.AllowFieldCycles = true
+
+	_ = autowired.Register[CyclicA](container, func() *CyclicA { return &CyclicA{} })
+	_ = autowired.Register[CyclicB](container, func() *CyclicB { return &CyclicB{} })
+
+	a, err = autowired.Resolve[*CyclicA](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve CyclicA with field cycles: %v", err)
+	}
+
+	b, err := autowired.Resolve[*CyclicB](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve CyclicB with field cycles: %v", err)
+	}
+
+	if a.B != b || b.A != a {
+		t.Error("CyclicA and CyclicB should reference each other's singleton")
+	}
+}
+
+// Test concurrent resolutions of both sides of a field cycle complete instead of deadlocking
+func TestAllowFieldCyclesConcurrent(t *testing.T) {
+	container := autowired.NewContainer()
+	container.AllowFieldCycles = true
+
+	// Each constructor waits briefly for the other, so unserialized constructions hold both at once
+	var entered int32
+	both := make(chan struct{})
+	enter := func() {
+		if atomic.AddInt32(&entered, 1) == 2 {
+			close(both)
+		}
+		select {
+		case <-both:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	_ = autowired.Register[CyclicA](container, func() *CyclicA { enter(); return &CyclicA{} })
+	_ = autowired.Register[CyclicB](container, func() *CyclicB { enter(); return &CyclicB{} })
+
+	var a *CyclicA
+	var b *CyclicB
+	var errA, errB error
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		a, errA = autowired.Resolve[*CyclicA](container)
+	}()
+	go func() {
+		defer wg.Done()
+		b, errB = autowired.Resolve[*CyclicB](container)
+	}()
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Concurrent resolutions of a field cycle deadlocked")
+	}
+
+	if errA != nil || errB != nil {
+		t.Fatalf("Failed to resolve the field cycle: %v, %v", errA, errB)
+	}
+	if a.B != b || b.A != a {
+		t.Error("CyclicA and CyclicB should reference each other's singleton")
+	}
+}
+
+// Test fetching a registered constructor for direct invocation
+func TestConstructorFor(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[TestService](container, NewTestService)
+	if err != nil {
+		t.Fatalf("Failed to register TestService: %v", err)
+	}
+
+	constructor, ok := container.ConstructorFor(reflect.TypeOf(&TestService{}), "")
+	if !ok {
+		t.Fatal("Expected constructor for TestService")
+	}
+
+	newService, ok := constructor.(func() *TestService)
+	if !ok {
+		t.Fatalf("Expected constructor of type func() *TestService, got %T", constructor)
+	}
+	if newService().Value != "default" {
+		t.Error("Constructor should build a default TestService")
+	}
+
+	if _, ok := container.ConstructorFor(reflect.TypeOf(&TestService{}), "missing"); ok {
+		t.Error("Expected no constructor for an unregistered name")
+	}
+}
+
+// Test invoking a function with resolved parameters
+func TestInvoke(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[TestService](container, NewTestService)
+	if err != nil {
+		t.Fatalf("Failed to register TestService: %v", err)
+	}
+	err = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{ID: 7}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register RequestContext: %v", err)
+	}
+
+	ran := false
+	err = container.Invoke(context.Background(), func(s *TestService, r *RequestContext) error {
+		ran = s.Value == "default" && r.ID == 7
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to invoke function: %v", err)
+	}
+	if !ran {
+		t.Error("Invoked function should have run with resolved dependencies")
+	}
+
+	invokeErr := errors.New("bootstrap failed")
+	err = container.Invoke(context.Background(), func(s *TestService) error {
+		return invokeErr
+	})
+	if !errors.Is(err, invokeErr) {
+		t.Errorf("Expected the function's error, got %v", err)
+	}
+
+	err = container.Invoke(context.Background(), func(g Greeter) {})
+	if err == nil {
+		t.Error("Expected error for an unregistered parameter type, got nil")
+	}
+}
+
+// Test invoking a function and returning its typed result
+func TestInvoke1(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{ID: 7}
+	})
+
+	summary, err := autowired.Invoke1[string](context.Background(), container, func(s *TestService, r *RequestContext) (string, error) {
+		return fmt.Sprintf("%s-%d", s.Value, r.ID), nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to invoke function: %v", err)
+	}
+	if summary != "default-7" {
+		t.Errorf("Expected 'default-7', got '%s'", summary)
+	}
+
+	_, err = autowired.Invoke1[int](context.Background(), container, func(s *TestService) string {
+		return s.Value
+	})
+	if err == nil {
+		t.Error("Expected error when the function's result does not match R, got nil")
+	}
+}
+
+// Test Start attempts every singleton when continuing on error
+func TestStartContinueOnError(t *testing.T) {
+	container := autowired.NewContainer()
+	container.StartContinueOnError = true
+
+	destroyed := map[string]bool{}
+	hooksFor := func(name string, startErr error) autowired.LifecycleHooks[*TestService] {
+		return autowired.LifecycleHooks[*TestService]{
+			OnStart: func(s *TestService) error {
+				return startErr
+			},
+			OnDestroy: func(s *TestService) error {
+				destroyed[name] = true
+				return nil
+			},
+		}
+	}
+
+	startErr := errors.New("optional component unavailable")
+	_ = autowired.Register[TestService](container, NewTestService, "first", hooksFor("first", nil))
+	_ = autowired.Register[TestService](container, NewTestService, "optional", hooksFor("optional", startErr))
+	_ = autowired.Register[TestService](container, NewTestService, "second", hooksFor("second", nil))
+
+	err := container.Start(context.Background())
+	if !errors.Is(err, startErr) {
+		t.Fatalf("Expected aggregated start error, got %v", err)
+	}
+
+	if err := container.Destroy(); err != nil {
+		t.Fatalf("Failed to destroy container: %v", err)
+	}
+
+	if !destroyed["first"] || !destroyed["second"] {
+		t.Error("Started components should be destroyed")
+	}
+	if destroyed["optional"] {
+		t.Error("Component that failed to start should not be destroyed")
+	}
+}
+
+// Test factories declaring their dependencies as parameters
+func TestRegisterFactoryFunc(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{ID: 3}
+	})
+
+	err := autowired.RegisterFactoryFunc[Greeter](container, func(ctx context.Context, s *TestService, r *RequestContext) (Greeter, error) {
+		return &englishGreeter{Service: &TestService{Value: fmt.Sprintf("%s-%d", s.Value, r.ID)}}, nil
+	}, autowired.Prototype)
+	if err != nil {
+		t.Fatalf("Failed to register Greeter factory: %v", err)
+	}
+
+	greeter, err := autowired.Resolve[Greeter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Greeter: %v", err)
+	}
+	if greeter.Greet() != "hello default-3" {
+		t.Errorf("Expected 'hello default-3', got '%s'", greeter.Greet())
+	}
+
+	err = autowired.RegisterFactoryFunc[Greeter](container, func(s *TestService) *TestService { return s })
+	if err == nil {
+		t.Error("Expected error for a factory not returning Greeter, got nil")
+	}
+}
+
+// Test a factory-built singleton runs its hooks and is destroyed with the container
+func TestRegisterSingletonFactoryWithHooks(t *testing.T) {
+	container := autowired.NewContainer()
+
+	var events []string
+	err := autowired.RegisterSingletonFactoryWithHooks[*TestService](container, func(ctx context.Context, c *autowired.Container) (*TestService, error) {
+		return &TestService{Value: "factory"}, nil
+	}, autowired.LifecycleHooks[*TestService]{
+		OnInit: func(s *TestService) error {
+			events = append(events, "init "+s.Value)
+			return nil
+		},
+		OnDestroy: func(s *TestService) error {
+			events = append(events, "destroy "+s.Value)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register factory with hooks: %v", err)
+	}
+
+	if _, err := autowired.Resolve[*TestService](container); err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+	if err := container.Destroy(); err != nil {
+		t.Fatalf("Failed to destroy container: %v", err)
+	}
+
+	if strings.Join(events, ",") != "init factory,destroy factory" {
+		t.Errorf("Expected init and destroy hooks to run, got %v", events)
+	}
+
+	err = container.RegisterFactoryWithHooks(reflect.TypeOf(&TestService{}), autowired.Singleton, func(ctx context.Context, c *autowired.Container) (interface{}, error) {
+		return &TestService{}, nil
+	}, "not hooks")
+	if !errors.Is(err, &autowired.ErrInvalidConstructor{}) {
+		t.Errorf("Expected ErrInvalidConstructor for invalid hooks, got %v", err)
+	}
+}
+
+// Test Close runs every destroy hook and reports all of their failures
+func TestClose(t *testing.T) {
+	container := autowired.NewContainer()
+
+	destroyed := 0
+	failing := func(s *TestService) error {
+		destroyed++
+		return fmt.Errorf("cannot release %s", s.Value)
+	}
+	_ = autowired.Register[TestService](container, NewTestService, autowired.LifecycleHooks[*TestService]{OnDestroy: failing})
+	_ = autowired.Register[TestService](container, func() *TestService {
+		return &TestService{Value: "backup"}
+	}, "backup", autowired.LifecycleHooks[*TestService]{OnDestroy: failing})
+
+	_, _ = autowired.Resolve[*TestService](container)
+	_, _ = autowired.Resolve[*TestService](container, "backup")
+
+	var closer io.Closer = container
+	err := closer.Close()
+
+	var multi *autowired.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("Expected both destroy failures, got %v", err)
+	}
+	if destroyed != 2 {
+		t.Errorf("Expected every destroy hook to run, got %d", destroyed)
+	}
+}
+
+// Test Shutdown gives up on a slow stop hook at its deadline and names what did not stop
+func TestShutdown(t *testing.T) {
+	container := autowired.NewContainer()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	_ = autowired.Register[DiamondBase](container, func() *DiamondBase {
+		return &DiamondBase{}
+	}, autowired.LifecycleHooks[*DiamondBase]{OnDestroy: func(*DiamondBase) error {
+		t.Error("Expected the stop of DiamondBase to be skipped after the deadline")
+		return nil
+	}})
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft {
+		return &DiamondLeft{Base: b}
+	}, autowired.LifecycleHooks[*DiamondLeft]{OnDestroy: func(*DiamondLeft) error {
+		<-release
+		return nil
+	}})
+
+	if err := container.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start container: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := container.Shutdown(ctx)
+
+	var timeout *autowired.ErrShutdownTimeout
+	if !errors.As(err, &timeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected ErrShutdownTimeout with the deadline, got %v", err)
+	}
+	if len(timeout.Pending) != 2 || timeout.Pending[0].Type != reflect.TypeOf(&DiamondLeft{}) {
+		t.Errorf("Expected DiamondLeft and then DiamondBase pending, got %v", timeout.Pending)
+	}
+	if !strings.Contains(err.Error(), "DiamondLeft") {
+		t.Errorf("Expected the error to name the slow component, got %v", err)
+	}
+}
+
+type InitLeaf struct{}
+
+type InitMiddle struct {
+	Leaf *InitLeaf
+}
+
+type InitTop struct {
+	Middle *InitMiddle
+	Leaf   *InitLeaf
+}
+
+// Test a component's init hook runs only after the init hooks of its whole dependency chain
+func TestInitOrder(t *testing.T) {
+	container := autowired.NewContainer()
+
+	var order []string
+	_ = autowired.Register[InitLeaf](container, func() *InitLeaf { return &InitLeaf{} },
+		autowired.LifecycleHooks[*InitLeaf]{OnInit: func(*InitLeaf) error {
+			order = append(order, "leaf")
+			return nil
+		}})
+	_ = autowired.Register[InitMiddle](container, func(leaf *InitLeaf) *InitMiddle { return &InitMiddle{Leaf: leaf} },
+		autowired.LifecycleHooks[*InitMiddle]{OnInit: func(m *InitMiddle) error {
+			if m.Leaf == nil {
+				t.Error("Middle should be initialized with its leaf")
+			}
+			order = append(order, "middle")
+			return nil
+		}})
+	_ = autowired.Register[InitTop](container, func(middle *InitMiddle, leaf *InitLeaf) *InitTop {
+		return &InitTop{Middle: middle, Leaf: leaf}
+	}, autowired.LifecycleHooks[*InitTop]{OnInit: func(*InitTop) error {
+		order = append(order, "top")
+		return nil
+	}})
+
+	if _, err := autowired.Resolve[*InitTop](container); err != nil {
+		t.Fatalf("Failed to resolve InitTop: %v", err)
+	}
+
+	if !reflect.DeepEqual(order, []string{"leaf", "middle", "top"}) {
+		t.Errorf("Expected dependencies to be initialized first, got %v", order)
+	}
+}
+
+type closerStub struct{}
+
+func (closerStub) Close() error { return nil }
+
+type EmbeddedResource struct {
+	io.Closer
+	Name string
+}
+
+// Test resolving a registration through its embedded interface
+func TestRegisterWithEmbedded(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.RegisterWithEmbedded[EmbeddedResource](container, func() *EmbeddedResource {
+		return &EmbeddedResource{Closer: closerStub{}, Name: "resource"}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register EmbeddedResource: %v", err)
+	}
+
+	closer, err := autowired.Resolve[io.Closer](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve io.Closer: %v", err)
+	}
+
+	resource, err := autowired.Resolve[*EmbeddedResource](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve EmbeddedResource: %v", err)
+	}
+
+	if closer != io.Closer(resource) {
+		t.Error("io.Closer should resolve to the EmbeddedResource singleton")
+	}
+}
+
+type EmbeddedUser struct {
+	Closer io.Closer
+}
+
+// Test embedded interfaces share the scope of their registration and never replace another registration
+func TestRegisterWithEmbeddedBindings(t *testing.T) {
+	container := autowired.NewContainer()
+	container.Strict = true
+
+	err := autowired.RegisterWithEmbedded[EmbeddedResource](container, func() *EmbeddedResource {
+		return &EmbeddedResource{Closer: closerStub{}, Name: "resource"}
+	}, "resource")
+	if err != nil {
+		t.Fatalf("Failed to register EmbeddedResource: %v", err)
+	}
+	_ = autowired.Register[EmbeddedUser](container, func(closer io.Closer) *EmbeddedUser {
+		return &EmbeddedUser{Closer: closer}
+	})
+
+	if err := container.Validate(); err != nil {
+		t.Errorf("Expected the wiring to validate, got %v", err)
+	}
+	if err := container.ValidateLifetimes(); err != nil {
+		t.Errorf("Expected no captive dependency through an embedded interface, got %v", err)
+	}
+
+	user, err := autowired.Resolve[*EmbeddedUser](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve EmbeddedUser: %v", err)
+	}
+	resource, err := autowired.Resolve[*EmbeddedResource](container, "resource")
+	if err != nil {
+		t.Fatalf("Failed to resolve EmbeddedResource: %v", err)
+	}
+	if user.Closer != io.Closer(resource) {
+		t.Error("io.Closer should resolve to the EmbeddedResource singleton")
+	}
+
+	err = autowired.RegisterWithEmbedded[EmbeddedResource](container, func() *EmbeddedResource {
+		return &EmbeddedResource{Name: "other"}
+	}, "other")
+	if !errors.Is(err, &autowired.ErrAlreadyRegistered{}) {
+		t.Fatalf("Expected ErrAlreadyRegistered for a second io.Closer, got %v", err)
+	}
+	if _, err := autowired.Resolve[*EmbeddedResource](container, "other"); !errors.Is(err, &autowired.ErrNotRegistered{}) {
+		t.Errorf("Expected the conflicting registration not to be kept, got %v", err)
+	}
+}
+
+// Test singletons are rebuilt once their TTL expires
+func TestRegisterSingletonTTL(t *testing.T) {
+	container := autowired.NewContainer()
+
+	destroyed := 0
+	err := autowired.RegisterSingletonTTL[TestService](container, NewTestService, 20*time.Millisecond,
+		autowired.LifecycleHooks[*TestService]{
+			OnDestroy: func(s *TestService) error {
+				destroyed++
+				return nil
+			},
+		})
+	if err != nil {
+		t.Fatalf("Failed to register TestService with TTL: %v", err)
+	}
+
+	first, _ := autowired.Resolve[*TestService](container)
+	second, _ := autowired.Resolve[*TestService](container)
+	if first != second {
+		t.Error("Singleton should be cached until its TTL expires")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	third, err := autowired.Resolve[*TestService](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve expired TestService: %v", err)
+	}
+	if third == first {
+		t.Error("Singleton should be reconstructed after its TTL expires")
+	}
+	if destroyed != 1 {
+		t.Errorf("Expected the expired instance to be destroyed once, got %d", destroyed)
+	}
+}
+
+type Reporter struct {
+	Primary   *TestService
+	Secondary *TestService
+}
+
+// Test qualifying which named registration fills a constructor parameter
+func TestRegisterQualified(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[TestService](container, func() *TestService {
+		return &TestService{Value: "replica"}
+	}, "replica")
+
+	err := autowired.RegisterQualified[Reporter](container, func(primary, secondary *TestService) *Reporter {
+		return &Reporter{Primary: primary, Secondary: secondary}
+	}, map[int]string{1: "replica"})
+	if err != nil {
+		t.Fatalf("Failed to register Reporter: %v", err)
+	}
+
+	reporter, err := autowired.Resolve[*Reporter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Reporter: %v", err)
+	}
+
+	if reporter.Primary.Value != "default" || reporter.Secondary.Value != "replica" {
+		t.Errorf("Expected default and replica services, got '%s' and '%s'", reporter.Primary.Value, reporter.Secondary.Value)
+	}
+
+	err = autowired.RegisterQualified[Reporter](container, func(primary *TestService) *Reporter {
+		return &Reporter{Primary: primary}
+	}, map[int]string{1: "replica"})
+	if err == nil {
+		t.Error("Expected error for a qualifier on a missing parameter, got nil")
+	}
+}
+
+// Test resolving an ordered subset of named registrations
+func TestResolveSlice(t *testing.T) {
+	container := autowired.NewContainer()
+
+	for _, name := range []string{"auth", "logging", "metrics"} {
+		value := name
+		_ = autowired.Register[TestService](container, func() *TestService {
+			return &TestService{Value: value}
+		}, name)
+	}
+
+	values := func(services []*TestService) []string {
+		var result []string
+		for _, s := range services {
+			result = append(result, s.Value)
+		}
+		return result
+	}
+
+	subset, err := autowired.ResolveSlice[*TestService](context.Background(), container, "metrics", "auth")
+	if err != nil {
+		t.Fatalf("Failed to resolve slice: %v", err)
+	}
+	if got := values(subset); !reflect.DeepEqual(got, []string{"metrics", "auth"}) {
+		t.Errorf("Expected [metrics auth], got %v", got)
+	}
+
+	all, err := autowired.ResolveSlice[*TestService](context.Background(), container)
+	if err != nil {
+		t.Fatalf("Failed to resolve all: %v", err)
+	}
+	if got := values(all); !reflect.DeepEqual(got, []string{"auth", "logging", "metrics"}) {
+		t.Errorf("Expected all services sorted by name, got %v", got)
+	}
+
+	if _, err := autowired.ResolveSlice[*TestService](context.Background(), container, "missing"); err == nil {
+		t.Error("Expected error for an unregistered name, got nil")
+	}
+}
+
+// Test named autowiring fills what it can and reports every missing field
+func TestAutoWireNamed(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, func() *TestService {
+		return &TestService{Value: "primary"}
+	}, "primary")
+
+	type App struct {
+		Primary *TestService `autowire:"primary"`
+		Backup  *TestService `autowire:"backup"`
+		Audit   *TestService `autowire:"audit"`
+	}
+
+	app := &App{}
+	err := autowired.AutoWireNamed(container, app)
+
+	if app.Primary == nil || app.Primary.Value != "primary" {
+		t.Errorf("Expected the primary field to be filled, got %v", app.Primary)
+	}
+
+	var multi *autowired.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("Expected both missing fields to be reported, got %v", err)
+	}
+	for i, field := range []string{"Backup", "Audit"} {
+		if !strings.Contains(multi.Errors[i].Error(), field) || !errors.Is(multi.Errors[i], &autowired.ErrNotRegistered{}) {
+			t.Errorf("Expected ErrNotRegistered for field %s, got %v", field, multi.Errors[i])
+		}
+	}
+}
+
+// Test injecting every named registration into map and slice fields
+func TestAutoWireCollections(t *testing.T) {
+	container := autowired.NewContainer()
+
+	for _, name := range []string{"auth", "logging", "metrics"} {
+		value := name
+		_ = autowired.Register[TestService](container, func() *TestService {
+			return &TestService{Value: value}
+		}, name)
+	}
+
+	type App struct {
+		ByName   map[string]*TestService `autowire:""`
+		Ordered  []*TestService          `autowire:""`
+		Handlers map[string]Greeter      `autowire:""`
+	}
+
+	app := &App{}
+	if err := autowired.AutoWire(container, app); err != nil {
+		t.Fatalf("Failed to auto-wire collections: %v", err)
+	}
+
+	if len(app.ByName) != 3 || app.ByName["logging"].Value != "logging" {
+		t.Errorf("Expected three services keyed by name, got %v", app.ByName)
+	}
+	if len(app.Ordered) != 3 || app.Ordered[0].Value != "auth" || app.Ordered[2].Value != "metrics" {
+		t.Errorf("Expected three services sorted by name, got %v", app.Ordered)
+	}
+	if app.Handlers == nil || len(app.Handlers) != 0 {
+		t.Errorf("Expected an empty non-nil map for an unregistered element type, got %v", app.Handlers)
+	}
+
+	type Raw struct {
+		Data []byte `autowire:""`
+	}
+
+	// Collections of basic kinds are resolved like any other field
+	raw := &Raw{}
+	if err := autowired.AutoWire(container, raw); !errors.Is(err, &autowired.ErrNotRegistered{}) {
+		t.Errorf("Expected ErrNotRegistered for a []byte field, got %v", err)
+	}
+}
+
+type OptionalConfig struct {
+	Verbose bool
+}
+
+type ConfiguredService struct {
+	Config *OptionalConfig
+}
+
+// Test unregistered defaultable parameters are injected as zero values
+func TestRegisterDefaultable(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[ConfiguredService](container, func(config *OptionalConfig) *ConfiguredService {
+		return &ConfiguredService{Config: config}
+	}, autowired.Prototype)
+
+	if _, err := autowired.Resolve[*ConfiguredService](container); err == nil {
+		t.Error("Expected error for an unregistered parameter that is not defaultable, got nil")
+	}
+
+	autowired.RegisterDefaultable[*OptionalConfig](container)
+
+	service, err := autowired.Resolve[*ConfiguredService](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve ConfiguredService with defaultable parameter: %v", err)
+	}
+	if service.Config != nil {
+		t.Error("Expected nil OptionalConfig")
+	}
+}
+
+type SlowService struct {
+	Name string
+}
+
+// Test Start builds independent singletons concurrently
+func TestStartInitConcurrency(t *testing.T) {
+	container := autowired.NewContainer()
+	container.InitConcurrency = 4
+
+	// Every constructor waits until all four have entered, which only happens when they overlap
+	var entered, constructed, overlapped int32
+	all := make(chan struct{})
+	for _, name := range []string{"a", "b", "c", "d"} {
+		value := name
+		_ = autowired.Register[SlowService](container, func() *SlowService {
+			if atomic.AddInt32(&entered, 1) == 4 {
+				close(all)
+			}
+			select {
+			case <-all:
+				atomic.AddInt32(&overlapped, 1)
+			case <-time.After(5 * time.Second):
+			}
+			atomic.AddInt32(&constructed, 1)
+			return &SlowService{Name: value}
+		}, value)
+	}
+	_ = autowired.Register[Reporter](container, func(s *SlowService) *Reporter {
+		return &Reporter{}
+	}, autowired.Qualifiers{0: "a"})
+
+	if err := container.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start container: %v", err)
+	}
+
+	if atomic.LoadInt32(&constructed) != 4 {
+		t.Errorf("Expected 4 slow singletons to be constructed once, got %d", constructed)
+	}
+	if n := atomic.LoadInt32(&overlapped); n != 4 {
+		t.Errorf("Expected independent singletons to start concurrently, only %d of 4 overlapped", n)
+	}
+}
+
+func BenchmarkStartInitConcurrency(b *testing.B) {
+	for _, workers := range []int{1, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				container := autowired.NewContainer()
+				container.InitConcurrency = workers
+				for j := 0; j < 16; j++ {
+					_ = autowired.Register[SlowService](container, func() *SlowService {
+						time.Sleep(time.Millisecond)
+						return &SlowService{}
+					}, fmt.Sprintf("slow%d", j))
+				}
+				if err := container.Start(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+type PipelineStage struct {
+	Step string
+}
+
+// Test appended registrations resolve in registration order
+func TestRegisterAppend(t *testing.T) {
+	container := autowired.NewContainer()
+
+	for _, step := range []string{"decode", "validate", "persist"} {
+		value := step
+		err := autowired.RegisterAppend[PipelineStage](container, func() *PipelineStage {
+			return &PipelineStage{Step: value}
+		})
+		if err != nil {
+			t.Fatalf("Failed to append stage %s: %v", step, err)
+		}
+	}
+
+	stages, err := autowired.ResolveAllOrdered[*PipelineStage](context.Background(), container)
+	if err != nil {
+		t.Fatalf("Failed to resolve pipeline: %v", err)
+	}
+
+	var steps []string
+	for _, stage := range stages {
+		steps = append(steps, stage.Step)
+	}
+	if !reflect.DeepEqual(steps, []string{"decode", "validate", "persist"}) {
+		t.Errorf("Expected stages in registration order, got %v", steps)
+	}
+
+	if nodes := container.RegisteredTypes(); len(nodes) != 3 {
+		t.Errorf("Expected each stage to be its own registration, got %v", nodes)
+	}
+}
+
+// Test resolving one appended registration by its index
+func TestResolveIndexed(t *testing.T) {
+	container := autowired.NewContainer()
+
+	for _, step := range []string{"decode", "validate", "persist"} {
+		value := step
+		err := autowired.RegisterAppend[PipelineStage](container, func() *PipelineStage {
+			return &PipelineStage{Step: value}
+		})
+		if err != nil {
+			t.Fatalf("Failed to append stage %s: %v", step, err)
+		}
+	}
+
+	stage, err := autowired.ResolveIndexed[*PipelineStage](context.Background(), container, 1)
+	if err != nil {
+		t.Fatalf("Failed to resolve stage by index: %v", err)
+	}
+	if stage.Step != "validate" {
+		t.Errorf("Expected the middle stage, got %s", stage.Step)
+	}
+
+	for _, index := range []int{-1, 3} {
+		_, err := autowired.ResolveIndexed[*PipelineStage](context.Background(), container, index)
+		if !errors.Is(err, &autowired.ErrIndexOutOfRange{}) {
+			t.Errorf("Expected ErrIndexOutOfRange for index %d, got %v", index, err)
+		}
+	}
+}
+
+// Test refreshing a single singleton in place
+func TestRefresh(t *testing.T) {
+	container := autowired.NewContainer()
+
+	destroyed := 0
+	_ = autowired.Register[TestService](container, NewTestService, autowired.LifecycleHooks[*TestService]{
+		OnDestroy: func(s *TestService) error {
+			destroyed++
+			return nil
+		},
+	})
+
+	before, _ := autowired.Resolve[*TestService](container)
+
+	if err := container.Refresh(context.Background(), reflect.TypeOf(&TestService{}), ""); err != nil {
+		t.Fatalf("Failed to refresh TestService: %v", err)
+	}
+
+	after, _ := autowired.Resolve[*TestService](container)
+	if before == after {
+		t.Error("Refresh should replace the singleton instance")
+	}
+	if destroyed != 1 {
+		t.Errorf("Expected the old instance to be destroyed once, got %d", destroyed)
+	}
+
+	_ = autowired.Register[RequestContext](container, func() *RequestContext { return &RequestContext{} }, autowired.Prototype)
+	if err := container.Refresh(context.Background(), reflect.TypeOf(&RequestContext{}), ""); err == nil {
+		t.Error("Expected error when refreshing a prototype, got nil")
+	}
+}
+
+// Test reconstruction keeps the old singleton while the comparer reports the replacement equal
+func TestRefreshEqual(t *testing.T) {
+	container := autowired.NewContainer()
+
+	value := "v1"
+	started, destroyed := 0, 0
+	_ = autowired.Register[TestService](container, func() *TestService {
+		return &TestService{Value: value}
+	}, autowired.Equal(func(old, replacement interface{}) bool {
+		return old.(*TestService).Value == replacement.(*TestService).Value
+	}), autowired.LifecycleHooks[*TestService]{
+		OnStart: func(s *TestService) error {
+			started++
+			return nil
+		},
+		OnDestroy: func(s *TestService) error {
+			destroyed++
+			return nil
+		},
+	})
+
+	typ := reflect.TypeOf(&TestService{})
+	before, _ := autowired.Resolve[*TestService](container)
+
+	if err := container.Refresh(context.Background(), typ, ""); err != nil {
+		t.Fatalf("Failed to refresh TestService: %v", err)
+	}
+	if same, _ := autowired.Resolve[*TestService](container); same != before {
+		t.Error("An equal reconstruction should keep the old instance")
+	}
+	if started != 1 || destroyed != 0 {
+		t.Errorf("An equal reconstruction should not restart or destroy anything, got %d starts and %d destroys", started, destroyed)
+	}
+
+	value = "v2"
+	if err := container.Refresh(context.Background(), typ, ""); err != nil {
+		t.Fatalf("Failed to refresh TestService: %v", err)
+	}
+	after, _ := autowired.Resolve[*TestService](container)
+	if after == before || after.Value != "v2" {
+		t.Errorf("A different reconstruction should replace the instance, got %v", after.Value)
+	}
+	if started != 2 || destroyed != 1 {
+		t.Errorf("Expected the replacement to start and the old instance to be destroyed, got %d starts and %d destroys", started, destroyed)
+	}
+}
+
+// Test generic registration rejects constructors that do not build T
+func TestRegisterTypeMismatch(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[TestService](container, func() *RequestContext {
+		return &RequestContext{}
+	})
+	if !errors.Is(err, &autowired.ErrInvalidConstructor{}) {
+		t.Fatalf("Expected ErrInvalidConstructor for a mismatched constructor, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "not assignable to autowired_test.TestService") {
+		t.Errorf("Expected a clear mismatch message, got '%s'", err.Error())
+	}
+
+	if err := autowired.Register[Greeter](container, func() *englishGreeter { return &englishGreeter{} }); err != nil {
+		t.Errorf("Constructors returning an implementation of T should be accepted, got %v", err)
+	}
+	if err := autowired.Register[TestService](container, NewTestService); err != nil {
+		t.Errorf("Constructors returning *T should be accepted, got %v", err)
+	}
+}
+
+// Test start hooks only run for the scopes they are meant for
+func TestHooksRunOn(t *testing.T) {
+	container := autowired.NewContainer()
+
+	started := make(map[string]int)
+	hooks := func(name string, scopes ...autowired.Scope) autowired.LifecycleHooks[*TestService] {
+		return autowired.LifecycleHooks[*TestService]{
+			OnStart: func(*TestService) error {
+				started[name]++
+				return nil
+			},
+			RunOn: scopes,
+		}
+	}
+
+	_ = autowired.Register[TestService](container, NewTestService, "singleton", hooks("singleton", autowired.Singleton))
+	_ = autowired.Register[TestService](container, NewTestService, "transient", autowired.Prototype, hooks("transient", autowired.Singleton))
+	_ = autowired.Register[TestService](container, NewTestService, "default", autowired.Prototype, hooks("default"))
+	_ = autowired.Register[TestService](container, NewTestService, "optIn", autowired.Prototype, hooks("optIn", autowired.Prototype))
+
+	for _, name := range []string{"singleton", "transient", "default", "optIn"} {
+		if _, err := autowired.Resolve[*TestService](container, name); err != nil {
+			t.Fatalf("Failed to resolve %s: %v", name, err)
+		}
+	}
+
+	expected := map[string]int{"singleton": 1, "optIn": 1}
+	if !reflect.DeepEqual(started, expected) {
+		t.Errorf("Expected start hooks %v, got %v", expected, started)
+	}
+}
+
+// Test warming a root constructs its whole subtree, including lazily resolved declared dependencies
+func TestWarm(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+
+	built := make(map[string]int)
+	_ = autowired.Register[DiamondTop](container, func(l *DiamondLeft, r *DiamondRight) *DiamondTop {
+		built["top"]++
+		return &DiamondTop{Left: l, Right: r}
+	}, autowired.Prototype)
+	_ = autowired.Register[TestService](container, func() *TestService {
+		built["service"]++
+		return &TestService{}
+	})
+	_ = container.RegisterFactory(reflect.TypeOf(&Reporter{}), func(ctx context.Context, c *autowired.Container) (interface{}, error) {
+		return &Reporter{}, nil
+	})
+	_ = container.SetDependencies(reflect.TypeOf(&Reporter{}), "",
+		autowired.DependencyNode{Type: reflect.TypeOf(&TestService{}), Name: "testService"})
+
+	if err := container.Warm(context.Background(), reflect.TypeOf(&DiamondTop{})); err != nil {
+		t.Fatalf("Failed to warm DiamondTop: %v", err)
+	}
+	if err := container.Warm(context.Background(), reflect.TypeOf(&Reporter{})); err != nil {
+		t.Fatalf("Failed to warm Reporter: %v", err)
+	}
+
+	dump := container.DumpInstances()
+	for _, node := range []string{
+		"*autowired_test.DiamondBase (diamondBase)",
+		"*autowired_test.DiamondLeft (diamondLeft)",
+		"*autowired_test.DiamondRight (diamondRight)",
+		"*autowired_test.Reporter (reporter)",
+		"*autowired_test.TestService (testService)",
+	} {
+		if _, ok := dump[node]; !ok {
+			t.Errorf("Expected %s to be constructed, got %v", node, dump)
+		}
+	}
+	if built["top"] != 0 || built["service"] != 1 {
+		t.Errorf("Expected the prototype root to be skipped and the declared dependency built once, got %v", built)
+	}
+}
+
+// recordingHooks returns hooks appending "start name" and "stop name" to events
+func recordingHooks[T any](events *[]string, name string) autowired.LifecycleHooks[T] {
+	return autowired.LifecycleHooks[T]{
+		OnStart: func(T) error {
+			*events = append(*events, "start "+name)
+			return nil
+		},
+		OnDestroy: func(T) error {
+			*events = append(*events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+// Test phases order startup and teardown beyond what the graph expresses
+func TestRegisterSingletonWithPhase(t *testing.T) {
+	container := autowired.NewContainer()
+
+	var events []string
+	service := func(name string) func() *TestService {
+		return func() *TestService { return &TestService{Value: name} }
+	}
+
+	// The HTTP server has no edge to the database but must drain before it closes
+	_ = autowired.RegisterSingletonWithPhase[TestService](container, service("http"),
+		recordingHooks[*TestService](&events, "http"), 0, "http")
+	_ = autowired.RegisterSingletonWithPhase[TestService](container, service("db"),
+		recordingHooks[*TestService](&events, "db"), 1, "db")
+	_ = autowired.RegisterSingletonWithPhase[DiamondBase](container, func() *DiamondBase {
+		return &DiamondBase{}
+	}, recordingHooks[*DiamondBase](&events, "base"), 0)
+	_ = autowired.RegisterSingletonWithPhase[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft {
+		return &DiamondLeft{Base: b}
+	}, recordingHooks[*DiamondLeft](&events, "left"), 0)
+
+	if err := container.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start container: %v", err)
+	}
+	if err := container.Destroy(); err != nil {
+		t.Fatalf("Failed to destroy container: %v", err)
+	}
+
+	expected := []string{
+		"start db", "start base", "start http", "start left",
+		"stop left", "stop base", "stop http", "stop db",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected events %v, got %v", expected, events)
+	}
+}
+
+// Test warmup hooks run after every start hook, in dependency order
+func TestWarmup(t *testing.T) {
+	container := autowired.NewContainer()
+
+	var events []string
+	hooks := func(name string) autowired.LifecycleHooks[interface{}] {
+		return autowired.LifecycleHooks[interface{}]{
+			OnStart: func(interface{}) error {
+				events = append(events, "start "+name)
+				return nil
+			},
+			OnWarmup: func(interface{}) error {
+				events = append(events, "warmup "+name)
+				return nil
+			},
+		}
+	}
+
+	_ = autowired.Register[DiamondBase](container, func() *DiamondBase { return &DiamondBase{} }, hooks("base"))
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft {
+		return &DiamondLeft{Base: b}
+	}, hooks("left"))
+
+	if err := container.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start container: %v", err)
+	}
+	if err := container.Warmup(context.Background()); err != nil {
+		t.Fatalf("Failed to warm up container: %v", err)
+	}
+
+	expected := []string{"start base", "start left", "warmup base", "warmup left"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected events %v, got %v", expected, events)
+	}
+}
+
+// Test a dependency shared by batch roots is constructed once
+func TestResolveBatch(t *testing.T) {
+	container := autowired.NewContainer()
+
+	baseBuilt := 0
+	_ = autowired.Register[DiamondBase](container, func() *DiamondBase {
+		baseBuilt++
+		return &DiamondBase{}
+	})
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft { return &DiamondLeft{Base: b} })
+	_ = autowired.Register[DiamondRight](container, func(b *DiamondBase) *DiamondRight { return &DiamondRight{Base: b} })
+
+	roots, err := container.ResolveBatch(context.Background(), reflect.TypeOf(&DiamondLeft{}), reflect.TypeOf(&DiamondRight{}))
+	if err != nil {
+		t.Fatalf("Failed to resolve batch: %v", err)
+	}
+
+	left, ok := roots[0].(*DiamondLeft)
+	if !ok {
+		t.Fatalf("Expected first root to be *DiamondLeft, got %T", roots[0])
+	}
+	right, ok := roots[1].(*DiamondRight)
+	if !ok {
+		t.Fatalf("Expected second root to be *DiamondRight, got %T", roots[1])
+	}
+
+	if baseBuilt != 1 || left.Base != right.Base {
+		t.Errorf("Expected shared DiamondBase to be built once, built %d times", baseBuilt)
+	}
+
+	if _, err := container.ResolveBatch(context.Background(), reflect.TypeOf(&DiamondLeft{}), reflect.TypeOf(&DiamondTop{})); err == nil {
+		t.Error("Expected error for an unregistered batch root, got nil")
+	}
+}
+
+// Test a factory that overruns its ConstructTimeout fails and is not cached
+func TestConstructTimeout(t *testing.T) {
+	container := autowired.NewContainer()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	var calls int32
+	err := container.RegisterFactory(reflect.TypeOf(&SlowService{}), func(ctx context.Context, c *autowired.Container) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+		}
+		return &SlowService{Name: "connected"}, nil
+	}, autowired.ConstructTimeout(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	_, err = container.Resolve(reflect.TypeOf(&SlowService{}))
+	if !errors.Is(err, &autowired.ErrConstructTimeout{}) {
+		t.Fatalf("Expected ErrConstructTimeout, got %v", err)
+	}
+
+	instance, err := container.Resolve(reflect.TypeOf(&SlowService{}))
+	if err != nil {
+		t.Fatalf("Failed to resolve after timeout: %v", err)
+	}
+	if instance.(*SlowService).Name != "connected" {
+		t.Errorf("Expected a freshly constructed instance, got %v", instance)
+	}
+}
+
+// Test a flaky factory is retried until it succeeds
+func TestRetryPolicy(t *testing.T) {
+	container := autowired.NewContainer()
+
+	attempts := 0
+	err := container.RegisterFactory(reflect.TypeOf(&SlowService{}), func(ctx context.Context, c *autowired.Container) (interface{}, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return &SlowService{Name: "connected"}, nil
+	}, autowired.RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	instance, err := container.Resolve(reflect.TypeOf(&SlowService{}))
+	if err != nil {
+		t.Fatalf("Failed to resolve with retries: %v", err)
+	}
+	if instance.(*SlowService).Name != "connected" || attempts != 3 {
+		t.Errorf("Expected success on the third attempt, got %v after %d attempts", instance, attempts)
+	}
+
+	err = container.RegisterFactory(reflect.TypeOf(&SlowService{}), func(ctx context.Context, c *autowired.Container) (interface{}, error) {
+		return nil, fmt.Errorf("connection refused")
+	}, "down", autowired.Prototype, autowired.RetryPolicy{Attempts: 5, Backoff: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := container.ResolveContext(ctx, reflect.TypeOf(&SlowService{}), "down"); !errors.Is(err, &autowired.ErrConstructorFailed{}) {
+		t.Errorf("Expected the last failure once the deadline passes, got %v", err)
+	}
+
+	// Singletons construct with a detached context, yet their retries still stop at the caller's deadline
+	err = container.RegisterFactory(reflect.TypeOf(&SlowService{}), func(ctx context.Context, c *autowired.Container) (interface{}, error) {
+		return nil, fmt.Errorf("connection refused")
+	}, "downSingleton", autowired.RetryPolicy{Attempts: 5, Backoff: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	singletonCtx, cancelSingleton := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelSingleton()
+	resolved := make(chan error, 1)
+	go func() {
+		_, err := container.ResolveContext(singletonCtx, reflect.TypeOf(&SlowService{}), "downSingleton")
+		resolved <- err
+	}()
+	select {
+	case err := <-resolved:
+		if !errors.Is(err, &autowired.ErrConstructorFailed{}) {
+			t.Errorf("Expected the last singleton failure once the deadline passes, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected singleton retries to stop at the caller's deadline")
+	}
+
+	dependencyBuilt := 0
+	_ = autowired.Register[TestService](container, func() (*TestService, error) {
+		dependencyBuilt++
+		return nil, fmt.Errorf("misconfigured")
+	}, autowired.Prototype)
+	err = container.RegisterFactory(reflect.TypeOf(&SlowService{}), func(ctx context.Context, c *autowired.Container) (interface{}, error) {
+		if _, err := autowired.ResolveContext[*TestService](ctx, c); err != nil {
+			return nil, fmt.Errorf("cannot build client: %w", err)
+		}
+		return &SlowService{}, nil
+	}, "client", autowired.Prototype, autowired.RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	if _, err := container.Resolve(reflect.TypeOf(&SlowService{}), "client"); err == nil {
+		t.Fatal("Expected the failing dependency to fail the factory")
+	}
+	if dependencyBuilt != 1 {
+		t.Errorf("Expected a dependency failure not to be retried, got %d attempts", dependencyBuilt)
+	}
+}
+
+// Test a name override map switches a whole subtree to named variants for one resolution
+func TestResolveWithNames(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+
+	var defaultBuilt, testBuilt int
+	_ = autowired.Register[DiamondBase](container, func() *DiamondBase {
+		defaultBuilt++
+		return &DiamondBase{}
+	}, "default")
+	_ = autowired.Register[DiamondBase](container, func() *DiamondBase {
+		testBuilt++
+		return &DiamondBase{}
+	}, "test", autowired.Prototype)
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft { return &DiamondLeft{Base: b} }, "test", autowired.Prototype)
+	_ = autowired.Register[DiamondRight](container, func(b *DiamondBase) *DiamondRight { return &DiamondRight{Base: b} }, "test", autowired.Prototype)
+	_ = autowired.Register[DiamondTop](container, func(l *DiamondLeft, r *DiamondRight) *DiamondTop {
+		return &DiamondTop{Left: l, Right: r}
+	}, "test", autowired.Prototype)
+
+	names := map[reflect.Type]string{
+		reflect.TypeOf(&DiamondTop{}):   "test",
+		reflect.TypeOf(&DiamondLeft{}):  "test",
+		reflect.TypeOf(&DiamondRight{}): "test",
+		reflect.TypeOf(&DiamondBase{}):  "test",
+	}
+	if _, err := container.ResolveWithNames(context.Background(), reflect.TypeOf(&DiamondTop{}), names); err != nil {
+		t.Fatalf("Failed to resolve with names: %v", err)
+	}
+	if testBuilt != 2 {
+		t.Errorf("Expected both branches to use the test DiamondBase, built %d times", testBuilt)
+	}
+
+	names[reflect.TypeOf(&DiamondBase{})] = "default"
+	if _, err := container.ResolveWithNames(context.Background(), reflect.TypeOf(&DiamondTop{}), names); err != nil {
+		t.Fatalf("Failed to resolve with names: %v", err)
+	}
+	if testBuilt != 2 || defaultBuilt != 1 {
+		t.Errorf("Expected both branches to share the singleton named default, built test %d and default %d times", testBuilt, defaultBuilt)
+	}
+}
+
+// Test an adapter builds the singleton while the constructor still describes its dependencies
+func TestRegisterSingletonFast(t *testing.T) {
+	container := autowired.NewContainer()
+	_ = autowired.Register[TestService](container, NewTestService)
+
+	adapterCalls := 0
+	err := autowired.RegisterSingletonFast[*Reporter](container, func(s *TestService) *Reporter {
+		t.Error("Constructor should not be called when an adapter is registered")
+		return nil
+	}, func(c *autowired.Container, ctx context.Context) (*Reporter, error) {
+		adapterCalls++
+		s, err := autowired.ResolveContext[*TestService](ctx, c)
+		return &Reporter{Primary: s}, err
+	})
+	if err != nil {
+		t.Fatalf("Failed to register fast singleton: %v", err)
+	}
+
+	reporter, err := autowired.Resolve[*Reporter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Reporter: %v", err)
+	}
+	if adapterCalls != 1 || reporter.Primary == nil {
+		t.Errorf("Expected the adapter to build Reporter once, called %d times", adapterCalls)
+	}
+
+	deps := container.PathsBetween(reflect.TypeOf(&Reporter{}), reflect.TypeOf(&TestService{}))
+	if len(deps) != 1 {
+		t.Errorf("Expected the constructor to describe the dependency on TestService, got %v", deps)
+	}
+}
+
+func BenchmarkConstructAdapter(b *testing.B) {
+	newReporter := func(s *TestService) *Reporter { return &Reporter{Primary: s} }
+	adapter := func(c *autowired.Container, ctx context.Context) (*Reporter, error) {
+		s, err := autowired.ResolveContext[*TestService](ctx, c)
+		return &Reporter{Primary: s}, err
+	}
+
+	register := map[string]func(c *autowired.Container) error{
+		"reflective": func(c *autowired.Container) error {
+			return autowired.Register[Reporter](c, newReporter)
+		},
+		"adapter": func(c *autowired.Container) error {
+			return autowired.RegisterSingletonFast[*Reporter](c, newReporter, adapter)
+		},
+	}
+
+	for _, mode := range []string{"reflective", "adapter"} {
+		b.Run(mode, func(b *testing.B) {
+			container := autowired.NewContainer()
+			_ = autowired.Register[TestService](container, NewTestService)
+			if err := register[mode](container); err != nil {
+				b.Fatal(err)
+			}
+
+			typ := reflect.TypeOf(&Reporter{})
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := container.Refresh(context.Background(), typ, ""); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+type RegionHandler struct {
+	Region string
+}
+
+// Test a constructor registered under two names sees the name it is resolved as
+func TestResolutionName(t *testing.T) {
+	container := autowired.NewContainer()
+
+	newHandler := func(name autowired.ResolutionName, s *TestService) *RegionHandler {
+		return &RegionHandler{Region: string(name)}
+	}
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[RegionHandler](container, newHandler, "east")
+	_ = autowired.Register[RegionHandler](container, newHandler, "west")
+
+	for _, name := range []string{"east", "west"} {
+		handler, err := autowired.Resolve[*RegionHandler](container, name)
+		if err != nil {
+			t.Fatalf("Failed to resolve %s handler: %v", name, err)
+		}
+		if handler.Region != name {
+			t.Errorf("Expected handler resolved as '%s' to see its name, got '%s'", name, handler.Region)
+		}
+	}
+}
+
+type frenchGreeter struct{}
+
+func (frenchGreeter) Greet() string {
+	return "bonjour"
+}
+
+// Test an unregistered interface resolves to its only registered implementation
+func TestResolveInterfaceImplementation(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[englishGreeter](container, func(s *TestService) *englishGreeter {
+		return &englishGreeter{Service: s}
+	})
+
+	greeter, err := autowired.Resolve[Greeter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Greeter through its implementation: %v", err)
+	}
+	if greeter.Greet() != "hello default" {
+		t.Errorf("Expected 'hello default', got '%s'", greeter.Greet())
+	}
+
+	_ = autowired.Register[frenchGreeter](container, func() frenchGreeter { return frenchGreeter{} })
+
+	_, err = autowired.Resolve[Greeter](container)
+	var ambiguous *autowired.ErrAmbiguousImplementation
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected ErrAmbiguousImplementation, got %v", err)
+	}
+	expected := []reflect.Type{reflect.TypeOf(&englishGreeter{}), reflect.TypeOf(frenchGreeter{})}
+	if !reflect.DeepEqual(ambiguous.Candidates, expected) {
+		t.Errorf("Expected candidates %v, got %v", expected, ambiguous.Candidates)
+	}
+}
+
+type PluginHost struct {
+	Plugins []string
+}
+
+// Test an init hook can register a dependency that later resolutions use
+func TestInitHookRegisters(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[PluginHost](container, func() *PluginHost {
+		return &PluginHost{Plugins: []string{"greeter"}}
+	}, autowired.InitHook(func(ctx context.Context, c *autowired.Container, instance interface{}) error {
+		for range instance.(*PluginHost).Plugins {
+			if err := autowired.Register[TestService](c, func() *TestService {
+				return &TestService{Value: "plugin"}
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Failed to register PluginHost: %v", err)
+	}
+
+	if _, err := autowired.Resolve[*TestService](container); !errors.Is(err, &autowired.ErrNotRegistered{}) {
+		t.Fatalf("Expected TestService to be unregistered before the hook runs, got %v", err)
+	}
+	if _, err := autowired.Resolve[*PluginHost](container); err != nil {
+		t.Fatalf("Failed to resolve PluginHost: %v", err)
+	}
+
+	service, err := autowired.Resolve[*TestService](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve the registration added by the init hook: %v", err)
+	}
+	if service.Value != "plugin" {
+		t.Errorf("Expected 'plugin', got '%s'", service.Value)
+	}
+
+	err = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Prototype, autowired.InitHook(func(ctx context.Context, c *autowired.Container, instance interface{}) error {
+		_, err := autowired.ResolveContext[*RequestContext](ctx, c)
+		return err
+	}))
+	if err != nil {
+		t.Fatalf("Failed to register RequestContext: %v", err)
+	}
+	if _, err := autowired.Resolve[*RequestContext](container); !errors.Is(err, &autowired.ErrCircularDependency{}) {
+		t.Errorf("Expected a self-resolving init hook to fail as circular, got %v", err)
+	}
+}
+
+// Test a shared registration hook is told which registration built each instance
+func TestRegistrationHook(t *testing.T) {
+	container := autowired.NewContainer()
+
+	seen := make(map[interface{}]autowired.Registration)
+	hook := autowired.RegistrationHook(func(reg autowired.Registration, instance interface{}) error {
+		seen[instance] = reg
+		return nil
+	})
+
+	_ = autowired.Register[TestService](container, NewTestService, "primary", hook)
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{ID: 7}
+	}, autowired.Prototype, hook)
+
+	service, err := autowired.Resolve[*TestService](container, "primary")
+	if err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+	request, err := autowired.Resolve[*RequestContext](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve RequestContext: %v", err)
+	}
+
+	expected := map[interface{}]autowired.Registration{
+		service: {Type: reflect.TypeOf(service), Name: "primary", Scope: autowired.Singleton},
+		request: {Type: reflect.TypeOf(request), Name: "requestContext", Scope: autowired.Prototype},
+	}
+	for instance, want := range expected {
+		got := seen[instance]
+		if got.Type != want.Type || got.Name != want.Name || got.Scope != want.Scope {
+			t.Errorf("Expected the hook to see %+v, got %+v", want, got)
+		}
+	}
+}
+
+// registerCountedSingletons registers n named singletons that count their constructions
+func registerCountedSingletons(container *autowired.Container, n int) []int32 {
+	built := make([]int32, n)
+	for i := 0; i < n; i++ {
+		i := i
+		_ = autowired.Register[SlowService](container, func() *SlowService {
+			atomic.AddInt32(&built[i], 1)
+			time.Sleep(time.Millisecond)
+			return &SlowService{}
+		}, fmt.Sprintf("slow%d", i))
+	}
+	return built
+}
+
+// Test concurrently resolved singletons are each constructed once
+func TestConcurrentDistinctSingletons(t *testing.T) {
+	container := autowired.NewContainer()
+	built := registerCountedSingletons(container, 16)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		for i := range built {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				if _, err := autowired.Resolve[*SlowService](container, name); err != nil {
+					t.Errorf("Failed to resolve %s: %v", name, err)
+				}
+			}(fmt.Sprintf("slow%d", i))
+		}
+	}
+	wg.Wait()
+
+	for i, n := range built {
+		if n != 1 {
+			t.Errorf("Expected slow%d to be constructed once, got %d", i, n)
+		}
+	}
+}
+
+func BenchmarkResolveDistinctSingletons(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		container := autowired.NewContainer()
+		built := registerCountedSingletons(container, 16)
+
+		var wg sync.WaitGroup
+		for j := range built {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				_, _ = autowired.Resolve[*SlowService](container, name)
+			}(fmt.Sprintf("slow%d", j))
+		}
+		wg.Wait()
+
+		for j, n := range built {
+			if n != 1 {
+				b.Fatalf("slow%d constructed %d times", j, n)
+			}
+		}
+	}
+}
+
+// Test an isolated resolution builds its own subtree and reports every node in it
+func TestResolveIsolated(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+
+	shared, err := autowired.Resolve[*DiamondLeft](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve DiamondLeft: %v", err)
+	}
+
+	root, built, err := container.ResolveIsolated(context.Background(), reflect.TypeOf(&DiamondTop{}))
+	if err != nil {
+		t.Fatalf("Failed to resolve isolated DiamondTop: %v", err)
+	}
+
+	top := root.(*DiamondTop)
+	nodes := container.RegisteredTypes()
+	if len(built) != len(nodes) {
+		t.Fatalf("Expected all %d nodes of the subtree, got %v", len(nodes), built)
+	}
+	for _, node := range nodes {
+		if _, ok := built[node]; !ok {
+			t.Errorf("Expected %v in the isolated graph", node)
+		}
+	}
+
+	left := built[autowired.DependencyNode{Type: reflect.TypeOf(&DiamondLeft{}), Name: "diamondLeft"}]
+	if left != top.Left || top.Left == shared {
+		t.Error("Expected the isolated graph to build its own DiamondLeft")
+	}
+
+	later, _ := autowired.Resolve[*DiamondTop](container)
+	if later == top {
+		t.Error("Expected the isolated root not to be cached in the container")
+	}
+}
+
+// Test parameters listed in ResolveOrder are resolved first
+func TestResolveOrder(t *testing.T) {
+	container := autowired.NewContainer()
+
+	var order []string
+	_ = autowired.Register[TestService](container, func() *TestService {
+		order = append(order, "TestService")
+		return &TestService{}
+	}, autowired.Prototype)
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		order = append(order, "RequestContext")
+		return &RequestContext{}
+	}, autowired.Prototype)
+	_ = autowired.Register[SlowService](container, func() *SlowService {
+		order = append(order, "SlowService")
+		return &SlowService{}
+	}, autowired.Prototype)
+
+	err := autowired.Register[Reporter](container, func(s *TestService, r *RequestContext, slow *SlowService) *Reporter {
+		return &Reporter{Primary: s}
+	}, autowired.ResolveOrder{2, 1})
+	if err != nil {
+		t.Fatalf("Failed to register Reporter: %v", err)
+	}
+
+	if _, err := autowired.Resolve[*Reporter](container); err != nil {
+		t.Fatalf("Failed to resolve Reporter: %v", err)
+	}
+	if expected := []string{"SlowService", "RequestContext", "TestService"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected resolution order %v, got %v", expected, order)
+	}
+
+	err = autowired.Register[Reporter](container, func(s *TestService) *Reporter {
+		return &Reporter{Primary: s}
+	}, autowired.ResolveOrder{0, 0})
+	if !errors.Is(err, &autowired.ErrInvalidConstructor{}) {
+		t.Errorf("Expected ErrInvalidConstructor for a repeated index, got %v", err)
+	}
+}
+
+// Test strict mode rejects a singleton capturing a prototype
+func TestStrictCaptiveDependency(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService, autowired.Prototype)
+	_ = autowired.Register[Reporter](container, func(s *TestService) *Reporter {
+		return &Reporter{Primary: s}
+	})
+	_ = autowired.Register[Reporter](container, func(s *TestService) *Reporter {
+		return &Reporter{Primary: s}
+	}, "perCall", autowired.Prototype)
+
+	if _, err := autowired.Resolve[*Reporter](container); err != nil {
+		t.Fatalf("Expected captive dependencies to be allowed outside strict mode, got %v", err)
+	}
+
+	container.Strict = true
+	_ = autowired.Register[Reporter](container, func(s *TestService) *Reporter {
+		return &Reporter{Primary: s}
+	})
+
+	_, err := autowired.Resolve[*Reporter](container)
+	var captive *autowired.ErrCaptiveDependency
+	if !errors.As(err, &captive) {
+		t.Fatalf("Expected ErrCaptiveDependency, got %v", err)
+	}
+	if captive.Dependency.Type != reflect.TypeOf(&TestService{}) || captive.Scope != autowired.Prototype {
+		t.Errorf("Expected the prototype TestService to be reported, got %v", captive)
+	}
+
+	if _, err := autowired.Resolve[*Reporter](container, "perCall"); err != nil {
+		t.Errorf("Expected a prototype depending on a prototype to resolve in strict mode, got %v", err)
+	}
+}
+
+// Test a bound interface resolves to the same singleton as its implementation
+func TestBindInterface(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[englishGreeter](container, func(s *TestService) *englishGreeter {
+		return &englishGreeter{Service: s}
+	})
+	// A second implementation would make automatic interface resolution ambiguous
+	_ = autowired.Register[frenchGreeter](container, func() frenchGreeter { return frenchGreeter{} })
+
+	greeterType := reflect.TypeOf((*Greeter)(nil)).Elem()
+	if err := container.BindInterface(greeterType, reflect.TypeOf(&englishGreeter{})); err != nil {
+		t.Fatalf("Failed to bind Greeter: %v", err)
+	}
+
+	greeter, err := autowired.Resolve[Greeter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Greeter: %v", err)
+	}
+	concrete, _ := autowired.Resolve[*englishGreeter](container)
+	if greeter != Greeter(concrete) {
+		t.Error("Expected the interface to resolve to the concrete singleton")
+	}
+
+	if err := container.BindInterface(greeterType, reflect.TypeOf(&TestService{})); err == nil {
+		t.Error("Expected error binding to a type that does not implement the interface, got nil")
+	}
+	if err := container.BindInterface(reflect.TypeOf((*io.Closer)(nil)).Elem(), reflect.TypeOf(closerStub{})); !errors.Is(err, &autowired.ErrNotRegistered{}) {
+		t.Errorf("Expected ErrNotRegistered binding to an unregistered type, got %v", err)
+	}
+}
+
+// Test OnReady fires once with the constructed singleton
+func TestOnReady(t *testing.T) {
+	container := autowired.NewContainer()
+	_ = autowired.Register[TestService](container, NewTestService)
+
+	var ready []interface{}
+	err := container.OnReady(reflect.TypeOf(&TestService{}), "", func(instance interface{}) {
+		ready = append(ready, instance)
+	})
+	if err != nil {
+		t.Fatalf("Failed to register OnReady callback: %v", err)
+	}
+	if len(ready) != 0 {
+		t.Fatal("Expected the callback to wait for the first resolution")
+	}
+
+	first, _ := autowired.Resolve[*TestService](container)
+	_, _ = autowired.Resolve[*TestService](container)
+
+	if len(ready) != 1 || ready[0] != first {
+		t.Errorf("Expected the callback to fire once with the singleton, got %v", ready)
+	}
+
+	late := 0
+	_ = container.OnReady(reflect.TypeOf(&TestService{}), "", func(instance interface{}) {
+		late++
+	})
+	if late != 1 {
+		t.Errorf("Expected a callback registered after construction to fire immediately, fired %d times", late)
+	}
+}
+
+// Test slice parameters collect only the named registrations declared for them
+func TestSliceQualifiers(t *testing.T) {
+	container := autowired.NewContainer()
+
+	for _, name := range []string{"memory", "disk", "remote"} {
+		value := name
+		_ = autowired.Register[TestService](container, func() *TestService {
+			return &TestService{Value: value}
+		}, name)
+	}
+
+	type tiers struct {
+		local, shared []*TestService
+	}
+	err := autowired.Register[tiers](container, func(local, shared []*TestService) *tiers {
+		return &tiers{local: local, shared: shared}
+	}, autowired.SliceQualifiers{0: {"memory", "disk"}, 1: {"remote"}})
+	if err != nil {
+		t.Fatalf("Failed to register tiers: %v", err)
+	}
+
+	resolved, err := autowired.Resolve[*tiers](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve tiers: %v", err)
+	}
+
+	values := func(services []*TestService) []string {
+		var v []string
+		for _, s := range services {
+			v = append(v, s.Value)
+		}
+		return v
+	}
+	if local := values(resolved.local); !reflect.DeepEqual(local, []string{"memory", "disk"}) {
+		t.Errorf("Expected local tier [memory disk], got %v", local)
+	}
+	if shared := values(resolved.shared); !reflect.DeepEqual(shared, []string{"remote"}) {
+		t.Errorf("Expected shared tier [remote], got %v", shared)
+	}
+
+	err = autowired.Register[Reporter](container, func(s *TestService) *Reporter {
+		return &Reporter{Primary: s}
+	}, autowired.SliceQualifiers{0: {"memory"}})
+	if !errors.Is(err, &autowired.ErrInvalidConstructor{}) {
+		t.Errorf("Expected ErrInvalidConstructor for a non-slice parameter, got %v", err)
+	}
+}
+
+// Test each dependency edge on a prototype gets its own instance within one resolution
+func TestPrototypePerEdge(t *testing.T) {
+	container := autowired.NewContainer()
+
+	built := 0
+	_ = autowired.Register[TestService](container, func() *TestService {
+		built++
+		return &TestService{Value: fmt.Sprint(built)}
+	}, autowired.Prototype)
+	_ = autowired.Register[Reporter](container, func(primary, secondary *TestService) *Reporter {
+		return &Reporter{Primary: primary, Secondary: secondary}
+	})
+
+	reporter, err := autowired.Resolve[*Reporter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Reporter: %v", err)
+	}
+	if built != 2 || reporter.Primary == reporter.Secondary {
+		t.Errorf("Expected a separate prototype per parameter, built %d times", built)
+	}
+}
@@ -0,0 +1,98 @@
+package autowired
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Definition accumulates the options of a registration and commits them on Register
+type Definition[T any] struct {
+	container   *Container
+	constructor interface{}
+	factory     Factory
+	options     []interface{}
+	err         error
+}
+
+// Define starts a fluent registration for T
+func Define[T any](c *Container) *Definition[T] {
+	return &Definition[T]{container: c}
+}
+
+// Named registers the dependency under name instead of the default name
+func (d *Definition[T]) Named(name string) *Definition[T] {
+	d.options = append(d.options, name)
+	return d
+}
+
+// Scope sets the lifecycle of the dependency
+func (d *Definition[T]) Scope(scope Scope) *Definition[T] {
+	d.options = append(d.options, scope)
+	return d
+}
+
+// Constructor sets the constructor whose parameters are resolved from the container
+func (d *Definition[T]) Constructor(constructor interface{}) *Definition[T] {
+	d.constructor = constructor
+	return d
+}
+
+// Factory sets a factory that builds the dependency instead of a constructor
+func (d *Definition[T]) Factory(factory func(ctx context.Context, c *Container) (T, error)) *Definition[T] {
+	d.factory = func(ctx context.Context, c *Container) (interface{}, error) {
+		return factory(ctx, c)
+	}
+	return d
+}
+
+// WithHooks attaches lifecycle hooks
+func (d *Definition[T]) WithHooks(hooks interface{}) *Definition[T] {
+	d.options = append(d.options, hooks)
+	return d
+}
+
+// Qualifiers sets the registration names injected into constructor parameters
+func (d *Definition[T]) Qualifiers(qualifiers map[int]string) *Definition[T] {
+	d.options = append(d.options, Qualifiers(qualifiers))
+	return d
+}
+
+// TTL expires the cached singleton after ttl, which must be positive
+func (d *Definition[T]) TTL(ttl time.Duration) *Definition[T] {
+	if ttl <= 0 {
+		d.err = fmt.Errorf("ttl must be positive")
+		return d
+	}
+	d.options = append(d.options, ttlOption(ttl))
+	return d
+}
+
+// Timeout fails construction that takes longer than timeout
+func (d *Definition[T]) Timeout(timeout time.Duration) *Definition[T] {
+	d.options = append(d.options, ConstructTimeout(timeout))
+	return d
+}
+
+// Tags labels the registration for lookup with Container.Tagged
+func (d *Definition[T]) Tags(tags ...string) *Definition[T] {
+	d.options = append(d.options, Tags(tags))
+	return d
+}
+
+// Register commits the definition to the container
+func (d *Definition[T]) Register() error {
+	switch {
+	case d.err != nil:
+		return d.err
+	case d.constructor != nil && d.factory != nil:
+		return &ErrInvalidConstructor{Reason: "definition must not have both a constructor and a factory"}
+	case d.constructor != nil:
+		return registerTyped[T](d.container, d.constructor, d.options...)
+	case d.factory != nil:
+		return d.container.RegisterFactory(reflect.TypeOf((*T)(nil)).Elem(), d.factory, d.options...)
+	default:
+		return &ErrInvalidConstructor{Reason: fmt.Sprintf("definition of %v has no constructor or factory", reflect.TypeOf((*T)(nil)).Elem())}
+	}
+}
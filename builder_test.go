@@ -0,0 +1,69 @@
+package autowired_test
+
+import (
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+)
+
+// Test building a fully specified registration fluently
+func TestDefine(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[TestService](container, func() *TestService {
+		return &TestService{Value: "replica"}
+	}, "replica")
+
+	initialized := false
+	err := autowired.Define[*Reporter](container).
+		Named("reporter").
+		Scope(autowired.Prototype).
+		Constructor(func(primary, secondary *TestService) *Reporter {
+			return &Reporter{Primary: primary, Secondary: secondary}
+		}).
+		Qualifiers(map[int]string{1: "replica"}).
+		WithHooks(autowired.LifecycleHooks[*Reporter]{
+			OnInit: func(r *Reporter) error {
+				initialized = true
+				return nil
+			},
+		}).
+		Tags("reporting", "admin").
+		Register()
+	if err != nil {
+		t.Fatalf("Failed to register Reporter fluently: %v", err)
+	}
+
+	first, err := autowired.Resolve[*Reporter](container, "reporter")
+	if err != nil {
+		t.Fatalf("Failed to resolve Reporter: %v", err)
+	}
+	second, _ := autowired.Resolve[*Reporter](container, "reporter")
+
+	if first == second {
+		t.Error("Prototype definition should produce different instances")
+	}
+	if first.Secondary.Value != "replica" {
+		t.Errorf("Expected qualified replica service, got '%s'", first.Secondary.Value)
+	}
+	if !initialized {
+		t.Error("OnInit hook should have been called")
+	}
+
+	expected := []autowired.DependencyNode{{Type: reflect.TypeOf(&Reporter{}), Name: "reporter"}}
+	if tagged := container.Tagged("admin"); !reflect.DeepEqual(tagged, expected) {
+		t.Errorf("Expected tagged registrations %v, got %v", expected, tagged)
+	}
+
+	if err := autowired.Define[*Reporter](container).Named("empty").Register(); err == nil {
+		t.Error("Expected error for a definition without a constructor, got nil")
+	}
+	err = autowired.Define[TestService](container).Named("expiring").Constructor(NewTestService).TTL(0).Register()
+	if err == nil {
+		t.Error("Expected error for a non-positive TTL, got nil")
+	}
+	if _, err := autowired.Resolve[*TestService](container, "expiring"); err == nil {
+		t.Error("Expected a definition with an invalid TTL not to be registered")
+	}
+}
@@ -0,0 +1,49 @@
+package autowired
+
+import (
+	"context"
+	"reflect"
+)
+
+// NewChild returns an empty container that falls back to c for everything it has no registration of.
+// Registrations in the child override those of the parent for resolutions made through the child. Dependencies
+// resolved from the parent are built entirely by the parent and never see the child's registrations.
+func (c *Container) NewChild() *Container {
+	child := NewContainer()
+	child.parent = c
+	return child
+}
+
+// implementationsOf returns the registrations of typ and, when typ is an interface, of every registered type
+// implementing it, sorted by type and then name
+func (c *Container) implementationsOf(typ reflect.Type) []DependencyNode {
+	var nodes []DependencyNode
+	for _, info := range c.registrations() {
+		if info.typ == typ || (typ.Kind() == reflect.Interface && info.typ.Implements(typ)) {
+			nodes = append(nodes, info.node())
+		}
+	}
+	return nodes
+}
+
+// ResolveAll resolves every registration of T, or of a type implementing T when T is an interface. Registrations
+// of the container come first, followed by those of its parent when IncludeParentInAll is set, and so on up
+// the hierarchy.
+func ResolveAll[T any](ctx context.Context, c *Container) ([]T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	var all []T
+	for container := c; container != nil; container = container.parent {
+		for _, node := range container.implementationsOf(typ) {
+			instance, err := container.ResolveContext(ctx, node.Type, node.Name)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, as[T](instance))
+		}
+		if !container.IncludeParentInAll {
+			break
+		}
+	}
+	return all, nil
+}
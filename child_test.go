@@ -0,0 +1,48 @@
+package autowired_test
+
+import (
+	"context"
+	"me.sithiramunasinghe/go-autowired"
+	"testing"
+)
+
+// Test a child container falls back to its parent and aggregates implementations across both
+func TestChildResolveAll(t *testing.T) {
+	parent := autowired.NewContainer()
+	_ = autowired.Register[TestService](parent, NewTestService)
+	_ = autowired.Register[englishGreeter](parent, func(s *TestService) *englishGreeter {
+		return &englishGreeter{Service: s}
+	})
+
+	child := parent.NewChild()
+	_ = autowired.Register[frenchGreeter](child, func() frenchGreeter { return frenchGreeter{} })
+
+	service, err := autowired.Resolve[*TestService](child)
+	if err != nil {
+		t.Fatalf("Failed to resolve TestService from the parent: %v", err)
+	}
+	if inParent, _ := autowired.Resolve[*TestService](parent); inParent != service {
+		t.Error("The child should share the parent's singleton")
+	}
+
+	greeters, err := autowired.ResolveAll[Greeter](context.Background(), child)
+	if err != nil {
+		t.Fatalf("Failed to resolve greeters: %v", err)
+	}
+	if len(greeters) != 1 || greeters[0].Greet() != "bonjour" {
+		t.Errorf("Expected only the child's greeter without IncludeParentInAll, got %v", greeters)
+	}
+
+	child.IncludeParentInAll = true
+	greeters, err = autowired.ResolveAll[Greeter](context.Background(), child)
+	if err != nil {
+		t.Fatalf("Failed to resolve greeters: %v", err)
+	}
+	var greetings []string
+	for _, g := range greeters {
+		greetings = append(greetings, g.Greet())
+	}
+	if len(greetings) != 2 || greetings[0] != "bonjour" || greetings[1] != "hello default" {
+		t.Errorf("Expected the child's greeter and then the parent's, got %v", greetings)
+	}
+}
@@ -0,0 +1,82 @@
+package autowired
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// SetConfigSource sets the source used to fill fields tagged with config:"key"
+func (c *Container) SetConfigSource(src func(key string) (string, bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.configSource = src
+}
+
+// injectConfig parses the configuration value for key into field
+func (c *Container) injectConfig(field reflect.Value, key string) error {
+	c.mu.RLock()
+	src := c.configSource
+	c.mu.RUnlock()
+
+	if src == nil {
+		return fmt.Errorf("no config source set for key '%s'", key)
+	}
+
+	raw, ok := src(key)
+	if !ok {
+		return fmt.Errorf("config key '%s' not found", key)
+	}
+
+	if err := setConfigValue(field, raw); err != nil {
+		return fmt.Errorf("invalid value for config key '%s': %w", key, err)
+	}
+	return nil
+}
+
+func setConfigValue(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported config field type %v", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package autowired_test
+
+import (
+	"me.sithiramunasinghe/go-autowired"
+	"testing"
+	"time"
+)
+
+// Test injecting configuration values from a map-backed source
+func TestConfigInjection(t *testing.T) {
+	container := autowired.NewContainer()
+
+	config := map[string]string{
+		"server.port":    "8080",
+		"server.timeout": "1m30s",
+		"server.debug":   "true",
+	}
+	container.SetConfigSource(func(key string) (string, bool) {
+		value, ok := config[key]
+		return value, ok
+	})
+
+	_ = autowired.Register[TestService](container, NewTestService)
+
+	type Server struct {
+		Service *TestService  `autowire:""`
+		Port    int           `config:"server.port"`
+		Timeout time.Duration `config:"server.timeout"`
+		Debug   bool          `config:"server.debug"`
+	}
+
+	server := &Server{}
+	if err := autowired.AutoWire(container, server); err != nil {
+		t.Fatalf("Failed to auto-wire Server: %v", err)
+	}
+
+	if server.Port != 8080 {
+		t.Errorf("Expected port 8080, got %d", server.Port)
+	}
+	if server.Timeout != 90*time.Second {
+		t.Errorf("Expected timeout 1m30s, got %v", server.Timeout)
+	}
+	if !server.Debug || server.Service == nil {
+		t.Error("Expected debug flag and service to be injected")
+	}
+
+	type Broken struct {
+		Port int `config:"server.missing"`
+	}
+	if err := autowired.AutoWire(container, &Broken{}); err == nil {
+		t.Error("Expected error for a missing config key, got nil")
+	}
+}
+
+type ConfiguredPeer struct {
+	Port int         `config:"port"`
+	Peer *CyclicPeer `autowire:""`
+}
+
+type CyclicPeer struct {
+	Owner *ConfiguredPeer `autowire:""`
+}
+
+// Test completing a field cycle leaves fields set by the constructor unconfigured
+func TestConfigFieldCycles(t *testing.T) {
+	container := autowired.NewContainer()
+	container.AllowFieldCycles = true
+
+	_ = autowired.Register[ConfiguredPeer](container, func() *ConfiguredPeer { return &ConfiguredPeer{Port: 9000} })
+	_ = autowired.Register[CyclicPeer](container, func() *CyclicPeer { return &CyclicPeer{} })
+
+	configured, err := autowired.Resolve[*ConfiguredPeer](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve ConfiguredPeer: %v", err)
+	}
+	if configured.Port != 9000 || configured.Peer == nil || configured.Peer.Owner != configured {
+		t.Errorf("Expected the constructed port and the completed cycle, got %+v", configured)
+	}
+}
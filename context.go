@@ -0,0 +1,181 @@
+package autowired
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// detachedContext carries selected values of its parent but never expires.
+// Singletons outlive the resolve that built them, so they must not inherit
+// the caller's deadline or cancellation.
+type detachedContext struct {
+	parent context.Context
+	keep   func(key interface{}) bool
+}
+
+func (d detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (d detachedContext) Done() <-chan struct{} { return nil }
+
+func (d detachedContext) Err() error { return nil }
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	switch key.(type) {
+	case resolutionPathKey, isolationKey, constructingKey, resolveMetaKey, fieldCycleKey:
+		return d.parent.Value(key)
+	case callerDoneKey:
+		if done := d.parent.Value(key); done != nil {
+			return done
+		}
+		return d.parent.Done()
+	}
+	if d.keep != nil && d.keep(key) {
+		return d.parent.Value(key)
+	}
+	return nil
+}
+
+func (c *Container) detachContext(ctx context.Context) context.Context {
+	if _, ok := ctx.(detachedContext); ok {
+		return ctx
+	}
+	return detachedContext{parent: ctx, keep: c.SingletonContextValues}
+}
+
+type callerDoneKey struct{}
+
+// callerDone returns the Done channel of the context a detached one was made from, which still stops retries
+func callerDone(ctx context.Context) <-chan struct{} {
+	done, _ := ctx.Value(callerDoneKey{}).(<-chan struct{})
+	return done
+}
+
+// lazyContext hides the state of the resolution in progress, for contexts kept by lazy accessors such as Provider
+// and resolved with after that resolution has finished
+type lazyContext struct {
+	context.Context
+}
+
+func (l lazyContext) Value(key interface{}) interface{} {
+	switch key.(type) {
+	case resolutionPathKey, isolationKey, constructingKey, resolveMetaKey:
+		return nil
+	}
+	return l.Context.Value(key)
+}
+
+type resolutionPathKey struct{}
+
+// resolutionPath returns the types currently being resolved on this call chain
+func resolutionPath(ctx context.Context) []reflect.Type {
+	path, _ := ctx.Value(resolutionPathKey{}).([]reflect.Type)
+	return path
+}
+
+func withResolving(ctx context.Context, path []reflect.Type, typ reflect.Type) context.Context {
+	next := make([]reflect.Type, len(path), len(path)+1)
+	copy(next, path)
+	return context.WithValue(ctx, resolutionPathKey{}, append(next, typ))
+}
+
+type nameOverridesKey struct{}
+
+func withNameOverrides(ctx context.Context, names map[reflect.Type]string) context.Context {
+	return context.WithValue(ctx, nameOverridesKey{}, names)
+}
+
+// nameOverride returns the name selected for typ by ResolveWithNames, if any
+func nameOverride(ctx context.Context, typ reflect.Type) string {
+	names, _ := ctx.Value(nameOverridesKey{}).(map[reflect.Type]string)
+	return names[typ]
+}
+
+// substitutionKey identifies the substitutions of one container in a context
+type substitutionKey struct {
+	container *Container
+}
+
+// Substitutions maps types to the instances they resolve to in a context carrying them under SubstitutionKey
+type Substitutions map[reflect.Type]interface{}
+
+// SubstitutionKey returns the key to store Substitutions under with context.WithValue. Resolutions using that
+// context return the substituted instance for a type, under any name, before looking at its registrations.
+// Singletons detach their context, so they are built from the registrations as usual and never capture one.
+func (c *Container) SubstitutionKey() interface{} {
+	return substitutionKey{container: c}
+}
+
+// substitute returns the instance substituted for typ in ctx, if any
+func (c *Container) substitute(ctx context.Context, typ reflect.Type) (interface{}, bool) {
+	substitutions, _ := ctx.Value(substitutionKey{container: c}).(Substitutions)
+	instance, ok := substitutions[typ]
+	return instance, ok
+}
+
+type isolationKey struct{}
+
+// isolation collects the instances built by one ResolveIsolated call
+type isolation struct {
+	mu        sync.Mutex
+	instances map[*dependencyInfo]interface{}
+}
+
+func isolationOf(ctx context.Context) *isolation {
+	iso, _ := ctx.Value(isolationKey{}).(*isolation)
+	return iso
+}
+
+func withIsolation(ctx context.Context, iso *isolation) context.Context {
+	return context.WithValue(ctx, isolationKey{}, iso)
+}
+
+// Param is a resolve option handing a value to the constructors and factories of that resolution
+type Param struct {
+	Key   string
+	Value interface{}
+}
+
+// WithParam passes key and value to the dependency being resolved. Constructors and factories read them with
+// ParamsFrom on the context they are given. Singletons detach their context, so only prototypes and
+// request-scoped dependencies see parameters.
+func WithParam(key string, value interface{}) Param {
+	return Param{Key: key, Value: value}
+}
+
+// Params holds the parameters passed to a resolution with WithParam
+type Params map[string]interface{}
+
+type paramsKey struct{}
+
+// ParamsFrom returns the parameters of the resolution ctx belongs to
+func ParamsFrom(ctx context.Context) Params {
+	params, _ := ctx.Value(paramsKey{}).(Params)
+	return params
+}
+
+// withParams adds the Param options to the parameters already carried by ctx, overriding keys passed again
+func withParams(ctx context.Context, options []interface{}) context.Context {
+	var params Params
+	for _, option := range options {
+		param, ok := option.(Param)
+		if !ok {
+			continue
+		}
+		if params == nil {
+			params = make(Params)
+			for key, value := range ParamsFrom(ctx) {
+				params[key] = value
+			}
+		}
+		params[param.Key] = param.Value
+	}
+
+	if params == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, paramsKey{}, params)
+}
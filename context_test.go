@@ -0,0 +1,175 @@
+package autowired_test
+
+import (
+	"context"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type ContextService struct {
+	Ctx context.Context
+}
+
+func NewContextService(ctx context.Context) *ContextService {
+	return &ContextService{Ctx: ctx}
+}
+
+type requestKey struct{}
+
+// Test that a singleton built during a request does not retain its cancellation
+func TestSingletonContextIsDetached(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[ContextService](container, NewContextService)
+	if err != nil {
+		t.Fatalf("Failed to register ContextService: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), requestKey{}, "req-1"), time.Minute)
+	service, err := autowired.ResolveContext[*ContextService](ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to resolve ContextService: %v", err)
+	}
+	cancel()
+
+	if service.Ctx.Err() != nil {
+		t.Errorf("Singleton context should not be cancelled with the request, got %v", service.Ctx.Err())
+	}
+	if _, ok := service.Ctx.Deadline(); ok {
+		t.Error("Singleton context should not carry the request deadline")
+	}
+	if service.Ctx.Value(requestKey{}) != nil {
+		t.Error("Singleton context should not carry request values by default")
+	}
+}
+
+// Test that selected values survive detaching
+func TestSingletonContextValueSelector(t *testing.T) {
+	container := autowired.NewContainer()
+	container.SingletonContextValues = func(key interface{}) bool {
+		return key == requestKey{}
+	}
+
+	err := autowired.Register[ContextService](container, NewContextService)
+	if err != nil {
+		t.Fatalf("Failed to register ContextService: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), requestKey{}, "req-1")
+	service, err := autowired.ResolveContext[*ContextService](ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to resolve ContextService: %v", err)
+	}
+
+	if service.Ctx.Value(requestKey{}) != "req-1" {
+		t.Errorf("Expected selected value 'req-1', got %v", service.Ctx.Value(requestKey{}))
+	}
+}
+
+// Test opting out of context detaching and context passing for other scopes
+func TestInheritContext(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[ContextService](container, NewContextService, autowired.InheritContext)
+	if err != nil {
+		t.Fatalf("Failed to register ContextService: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	service, err := autowired.ResolveContext[*ContextService](ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to resolve ContextService: %v", err)
+	}
+	cancel()
+
+	if service.Ctx.Err() == nil {
+		t.Error("Inherited context should be cancelled with the resolving context")
+	}
+
+	err = autowired.Register[ContextService](container, NewContextService, autowired.Prototype)
+	if err != nil {
+		t.Fatalf("Failed to register prototype ContextService: %v", err)
+	}
+
+	ctx = context.WithValue(context.Background(), requestKey{}, "req-2")
+	service, err = autowired.ResolveContext[*ContextService](ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to resolve prototype ContextService: %v", err)
+	}
+
+	if service.Ctx.Value(requestKey{}) != "req-2" {
+		t.Error("Prototype should receive the resolving context")
+	}
+}
+
+type Report struct {
+	Range string
+}
+
+// Test a prototype reads the parameters it was resolved with
+func TestResolveParams(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[Report](container, func(ctx context.Context) *Report {
+		rng, _ := autowired.ParamsFrom(ctx)["range"].(string)
+		return &Report{Range: rng}
+	}, autowired.Prototype)
+	if err != nil {
+		t.Fatalf("Failed to register Report: %v", err)
+	}
+
+	weekly, err := autowired.ResolveContext[*Report](context.Background(), container, autowired.WithParam("range", "7d"))
+	if err != nil {
+		t.Fatalf("Failed to resolve weekly Report: %v", err)
+	}
+	monthly, err := autowired.ResolveContext[*Report](context.Background(), container, autowired.WithParam("range", "30d"))
+	if err != nil {
+		t.Fatalf("Failed to resolve monthly Report: %v", err)
+	}
+
+	if weekly == monthly || weekly.Range != "7d" || monthly.Range != "30d" {
+		t.Errorf("Expected distinct reports for 7d and 30d, got %q and %q", weekly.Range, monthly.Range)
+	}
+
+	plain, _ := autowired.ResolveContext[*Report](context.Background(), container)
+	if plain.Range != "" {
+		t.Errorf("Expected no range without parameters, got %q", plain.Range)
+	}
+}
+
+type Experiment struct {
+	Service *TestService
+}
+
+// Test a substitution in the context swaps a dependency for that resolution only
+func TestContextSubstitution(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[Experiment](container, func(s *TestService) *Experiment {
+		return &Experiment{Service: s}
+	}, autowired.Prototype)
+
+	variant := &TestService{Value: "variant"}
+	ctx := context.WithValue(context.Background(), container.SubstitutionKey(), autowired.Substitutions{
+		reflect.TypeOf(variant): variant,
+	})
+
+	experiment, err := autowired.ResolveContext[*Experiment](ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Experiment: %v", err)
+	}
+	if experiment.Service != variant {
+		t.Errorf("Expected the substituted TestService, got %v", experiment.Service)
+	}
+
+	experiment, err = autowired.Resolve[*Experiment](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Experiment: %v", err)
+	}
+	if experiment.Service == variant || experiment.Service.Value != "default" {
+		t.Errorf("Expected the registered TestService without the substitution, got %v", experiment.Service)
+	}
+}
@@ -0,0 +1,147 @@
+package autowired
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// DumpInstances renders the cached singletons, keyed by "type (name)".
+// Instances whose String or Error method panics are rendered with the panic value instead.
+func (c *Container) DumpInstances() map[string]string {
+	dump := make(map[string]string)
+	for _, info := range c.singletons() {
+		if instance, ok := info.cached(); ok {
+			dump[info.node().String()] = render(instance)
+		}
+	}
+	return dump
+}
+
+func render(instance interface{}) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("<panic rendering %T: %v>", instance, r)
+		}
+	}()
+	return fmt.Sprintf("%+v", instance)
+}
+
+// VisualizeResolved renders the singletons constructed so far as an indented dependency tree.
+// Dependencies that are not cached, such as prototypes built inline, are marked "(not cached)".
+func (c *Container) VisualizeResolved() string {
+	infos := c.registrations()
+	byNode := make(map[DependencyNode]*dependencyInfo, len(infos))
+	for _, info := range infos {
+		byNode[info.node()] = info
+	}
+
+	isCached := func(node DependencyNode) bool {
+		info, ok := byNode[node]
+		if !ok || info.scope != Singleton {
+			return false
+		}
+		_, cached := info.cached()
+		return cached
+	}
+
+	// Roots are cached singletons that no other cached singleton depends on
+	dependedOn := make(map[DependencyNode]bool)
+	for _, info := range infos {
+		if isCached(info.node()) {
+			for _, dep := range c.dependenciesOf(info) {
+				dependedOn[dep] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	var write func(node DependencyNode, depth int, path map[DependencyNode]bool)
+	write = func(node DependencyNode, depth int, path map[DependencyNode]bool) {
+		indent := strings.Repeat("  ", depth)
+		if !isCached(node) {
+			fmt.Fprintf(&b, "%s%v (not cached)\n", indent, node)
+			return
+		}
+		fmt.Fprintf(&b, "%s%v (cached)\n", indent, node)
+
+		if path[node] {
+			return
+		}
+		path[node] = true
+		for _, dep := range c.dependenciesOf(byNode[node]) {
+			write(dep, depth+1, path)
+		}
+		delete(path, node)
+	}
+
+	for _, info := range infos {
+		if node := info.node(); isCached(node) && !dependedOn[node] {
+			write(node, 0, make(map[DependencyNode]bool))
+		}
+	}
+
+	return b.String()
+}
+
+// DetectDuplicates reports groups of registrations built by the same constructor function, each of which
+// would construct its own instance of the same value. Factory registrations are not compared, nor are those
+// like RegisterShared and RegisterSingletonFast that keep their constructor only to describe dependencies while
+// a factory provides the instance. Closures created by one function literal count as the same constructor.
+func (c *Container) DetectDuplicates() []string {
+	var order []uintptr
+	groups := make(map[uintptr][]string)
+	for _, info := range c.registrations() {
+		if !info.constructor.IsValid() || info.factory != nil {
+			continue
+		}
+		pc := info.constructor.Pointer()
+		if _, ok := groups[pc]; !ok {
+			order = append(order, pc)
+		}
+		groups[pc] = append(groups[pc], info.node().String())
+	}
+
+	var duplicates []string
+	for _, pc := range order {
+		if nodes := groups[pc]; len(nodes) > 1 {
+			name := "<unknown>"
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				name = fn.Name()
+			}
+			duplicates = append(duplicates, fmt.Sprintf("%s share constructor %s", strings.Join(nodes, ", "), name))
+		}
+	}
+	return duplicates
+}
+
+// DetectAmbiguous reports every interface that constructors depend on without it being registered or bound,
+// while several registered types implement it and none of their registrations is marked Primary. Resolving such
+// an interface fails with ErrAmbiguousImplementation.
+func (c *Container) DetectAmbiguous() []string {
+	seen := make(map[reflect.Type]bool)
+	var ambiguous []string
+	for _, deps := range c.graph() {
+		for _, dep := range deps {
+			if dep.Type.Kind() != reflect.Interface || seen[dep.Type] {
+				continue
+			}
+			seen[dep.Type] = true
+
+			c.mu.RLock()
+			_, err := c.getDependencyInfo(dep.Type, "")
+			c.mu.RUnlock()
+
+			var ambiguity *ErrAmbiguousImplementation
+			if errors.As(err, &ambiguity) {
+				ambiguous = append(ambiguous, fmt.Sprintf("%v is implemented by %v and none of them is marked Primary", ambiguity.Interface, ambiguity.Candidates))
+			}
+		}
+	}
+
+	sort.Strings(ambiguous)
+	return ambiguous
+}
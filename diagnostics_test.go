@@ -0,0 +1,121 @@
+package autowired_test
+
+import (
+	"context"
+	"me.sithiramunasinghe/go-autowired"
+	"strings"
+	"testing"
+)
+
+type panickyStringer struct{}
+
+func (*panickyStringer) String() string {
+	panic("cannot render")
+}
+
+// Test cached singletons appear in the instance dump
+func TestDumpInstances(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[RequestContext](container, func() *RequestContext { return &RequestContext{} })
+	_ = autowired.Register[panickyStringer](container, func() *panickyStringer { return &panickyStringer{} })
+
+	_, _ = autowired.Resolve[*TestService](container)
+	_, _ = autowired.Resolve[*panickyStringer](container)
+
+	dump := container.DumpInstances()
+
+	if dump["*autowired_test.TestService (testService)"] != "&{Value:default}" {
+		t.Errorf("Expected TestService in dump, got %v", dump)
+	}
+	if _, ok := dump["*autowired_test.RequestContext (requestContext)"]; ok {
+		t.Error("Unresolved singletons should not appear in dump")
+	}
+	if rendered := dump["*autowired_test.panickyStringer (panickyStringer)"]; !strings.Contains(rendered, "cannot render") {
+		t.Errorf("Expected recovered panic in dump, got '%s'", rendered)
+	}
+}
+
+// Test only constructed singletons are visualized
+func TestVisualizeResolved(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+
+	if tree := container.VisualizeResolved(); tree != "" {
+		t.Errorf("Expected empty tree before resolving, got:\n%s", tree)
+	}
+
+	if _, err := autowired.Resolve[*DiamondLeft](container); err != nil {
+		t.Fatalf("Failed to resolve DiamondLeft: %v", err)
+	}
+
+	expected := "*autowired_test.DiamondLeft (diamondLeft) (cached)\n" +
+		"  *autowired_test.DiamondBase (diamondBase) (cached)\n"
+	if tree := container.VisualizeResolved(); tree != expected {
+		t.Errorf("Expected tree:\n%s\ngot:\n%s", expected, tree)
+	}
+}
+
+// Test registrations sharing a constructor are flagged as duplicates
+func TestDetectDuplicates(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService, "primary")
+	_ = autowired.Register[TestService](container, NewTestService, "secondary")
+	_ = autowired.Register[RequestContext](container, func() *RequestContext { return &RequestContext{} })
+
+	// These keep NewTestService only to describe their dependencies, so they are not duplicates
+	_ = autowired.RegisterShared[*TestService](container, "service", NewTestService, "sharedA")
+	_ = autowired.RegisterShared[*TestService](container, "service", NewTestService, "sharedB")
+	_ = autowired.RegisterSingletonFast[*TestService](container, NewTestService, func(*autowired.Container, context.Context) (*TestService, error) {
+		return NewTestService(), nil
+	}, "fast")
+
+	duplicates := container.DetectDuplicates()
+	if len(duplicates) != 1 {
+		t.Fatalf("Expected one duplicate group, got %v", duplicates)
+	}
+	if !strings.Contains(duplicates[0], "(primary)") || !strings.Contains(duplicates[0], "(secondary)") || !strings.Contains(duplicates[0], "NewTestService") {
+		t.Errorf("Expected primary and secondary to share NewTestService, got '%s'", duplicates[0])
+	}
+	if strings.Contains(duplicates[0], "shared") || strings.Contains(duplicates[0], "(fast)") {
+		t.Errorf("Expected shared and fast registrations to be skipped, got '%s'", duplicates[0])
+	}
+}
+
+type GreetingHandler struct {
+	Greeter Greeter
+}
+
+// Test interfaces with several implementations and no primary are reported until one is marked Primary
+func TestDetectAmbiguous(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[englishGreeter](container, func(s *TestService) *englishGreeter {
+		return &englishGreeter{Service: s}
+	})
+	_ = autowired.Register[frenchGreeter](container, func() frenchGreeter { return frenchGreeter{} })
+	_ = autowired.Register[GreetingHandler](container, func(g Greeter) *GreetingHandler {
+		return &GreetingHandler{Greeter: g}
+	})
+
+	ambiguous := container.DetectAmbiguous()
+	if len(ambiguous) != 1 || !strings.Contains(ambiguous[0], "autowired_test.Greeter") {
+		t.Fatalf("Expected Greeter to be reported as ambiguous, got %v", ambiguous)
+	}
+
+	_ = autowired.Register[frenchGreeter](container, func() frenchGreeter { return frenchGreeter{} }, autowired.Primary)
+
+	if ambiguous := container.DetectAmbiguous(); len(ambiguous) != 0 {
+		t.Errorf("Expected no ambiguity once an implementation is primary, got %v", ambiguous)
+	}
+	handler, err := autowired.Resolve[*GreetingHandler](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve GreetingHandler: %v", err)
+	}
+	if handler.Greeter.Greet() != "bonjour" {
+		t.Errorf("Expected the primary implementation, got '%s'", handler.Greeter.Greet())
+	}
+}
@@ -0,0 +1,298 @@
+package autowired
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrNotRegistered is returned when no registration matches a resolved type and name
+type ErrNotRegistered struct {
+	Type reflect.Type
+	// Name is set when the type is registered, but not under this name
+	Name string
+}
+
+func (e *ErrNotRegistered) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("no dependency registered for type %v", e.Type)
+	}
+	return fmt.Sprintf("no dependency named '%s' registered for type %v", e.Name, e.Type)
+}
+
+// Is reports whether target is an ErrNotRegistered, so errors.Is(err, &ErrNotRegistered{}) matches any instance
+func (e *ErrNotRegistered) Is(target error) bool {
+	_, ok := target.(*ErrNotRegistered)
+	return ok
+}
+
+// ErrAlreadyRegistered is returned when a registration would replace another one it must not silently override
+type ErrAlreadyRegistered struct {
+	Type reflect.Type
+	Name string
+}
+
+func (e *ErrAlreadyRegistered) Error() string {
+	return fmt.Sprintf("%v (%s) is already registered", e.Type, e.Name)
+}
+
+// Is reports whether target is an ErrAlreadyRegistered
+func (e *ErrAlreadyRegistered) Is(target error) bool {
+	_, ok := target.(*ErrAlreadyRegistered)
+	return ok
+}
+
+// ErrCircularDependency is returned when a type depends on itself
+type ErrCircularDependency struct {
+	// Path lists the types of the cycle, starting and ending with the same type
+	Path []reflect.Type
+}
+
+func (e *ErrCircularDependency) Error() string {
+	return fmt.Sprintf("circular dependency detected for type %v", e.Path[len(e.Path)-1])
+}
+
+// Is reports whether target is an ErrCircularDependency
+func (e *ErrCircularDependency) Is(target error) bool {
+	_, ok := target.(*ErrCircularDependency)
+	return ok
+}
+
+// PathString renders the cycle as "A -> B -> A"
+func (e *ErrCircularDependency) PathString() string {
+	names := make([]string, len(e.Path))
+	for i, typ := range e.Path {
+		names[i] = typ.String()
+	}
+	return strings.Join(names, " -> ")
+}
+
+// ErrConstructorFailed wraps an error returned by a constructor or factory
+type ErrConstructorFailed struct {
+	Type reflect.Type
+	Err  error
+	// dependency is set when a factory failed because one of the dependencies it resolved failed
+	dependency bool
+}
+
+func (e *ErrConstructorFailed) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ErrConstructorFailed) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an ErrConstructorFailed
+func (e *ErrConstructorFailed) Is(target error) bool {
+	_, ok := target.(*ErrConstructorFailed)
+	return ok
+}
+
+// ErrInvalidConstructor is returned when registering something that cannot construct a dependency
+type ErrInvalidConstructor struct {
+	Reason string
+}
+
+func (e *ErrInvalidConstructor) Error() string {
+	return e.Reason
+}
+
+// Is reports whether target is an ErrInvalidConstructor
+func (e *ErrInvalidConstructor) Is(target error) bool {
+	_, ok := target.(*ErrInvalidConstructor)
+	return ok
+}
+
+// ErrAmbiguousImplementation is returned when an unregistered interface is implemented by several registered types
+type ErrAmbiguousImplementation struct {
+	Interface  reflect.Type
+	Candidates []reflect.Type
+}
+
+func (e *ErrAmbiguousImplementation) Error() string {
+	candidates := make([]string, len(e.Candidates))
+	for i, typ := range e.Candidates {
+		candidates[i] = typ.String()
+	}
+	return fmt.Sprintf("interface %v is implemented by several registered types: %s", e.Interface, strings.Join(candidates, ", "))
+}
+
+// Is reports whether target is an ErrAmbiguousImplementation
+func (e *ErrAmbiguousImplementation) Is(target error) bool {
+	_, ok := target.(*ErrAmbiguousImplementation)
+	return ok
+}
+
+// ErrOutsideScope is returned when a request-scoped dependency is resolved without a request scope
+type ErrOutsideScope struct {
+	Type reflect.Type
+	Name string
+}
+
+func (e *ErrOutsideScope) Error() string {
+	return fmt.Sprintf("request-scoped dependency %v (%s) resolved outside a scope", e.Type, e.Name)
+}
+
+// Is reports whether target is an ErrOutsideScope
+func (e *ErrOutsideScope) Is(target error) bool {
+	_, ok := target.(*ErrOutsideScope)
+	return ok
+}
+
+// ErrConstructTimeout is returned when constructing a dependency exceeds its ConstructTimeout
+type ErrConstructTimeout struct {
+	Type    reflect.Type
+	Name    string
+	Timeout time.Duration
+}
+
+func (e *ErrConstructTimeout) Error() string {
+	return fmt.Sprintf("constructing %v (%s) exceeded %v", e.Type, e.Name, e.Timeout)
+}
+
+// Is reports whether target is an ErrConstructTimeout
+func (e *ErrConstructTimeout) Is(target error) bool {
+	_, ok := target.(*ErrConstructTimeout)
+	return ok
+}
+
+// ErrShutdownTimeout is returned by Shutdown when its context ends before every singleton has stopped.
+// Pending lists the singletons whose OnDestroy hook was still running or never ran.
+type ErrShutdownTimeout struct {
+	Pending []DependencyNode
+	Err     error
+}
+
+func (e *ErrShutdownTimeout) Error() string {
+	pending := make([]string, len(e.Pending))
+	for i, node := range e.Pending {
+		pending[i] = node.String()
+	}
+	return fmt.Sprintf("shutdown ended (%v) before stopping %s", e.Err, strings.Join(pending, ", "))
+}
+
+func (e *ErrShutdownTimeout) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an ErrShutdownTimeout
+func (e *ErrShutdownTimeout) Is(target error) bool {
+	_, ok := target.(*ErrShutdownTimeout)
+	return ok
+}
+
+// ErrCaptiveDependency is returned in strict mode when a singleton depends on a shorter-lived registration,
+// and by ValidateLifetimes for any registration that does. Singleton is the longer-lived registration, of scope
+// HolderScope, and Scope is the scope of its Dependency.
+type ErrCaptiveDependency struct {
+	Singleton   DependencyNode
+	Dependency  DependencyNode
+	Scope       Scope
+	HolderScope Scope
+}
+
+func (e *ErrCaptiveDependency) Error() string {
+	return fmt.Sprintf("captive dependency: %s %v depends on %s %v", e.HolderScope, e.Singleton, e.Scope, e.Dependency)
+}
+
+// Is reports whether target is an ErrCaptiveDependency
+func (e *ErrCaptiveDependency) Is(target error) bool {
+	_, ok := target.(*ErrCaptiveDependency)
+	return ok
+}
+
+// ErrSealed is returned when a sealed container is asked to change a registration
+type ErrSealed struct {
+	Type reflect.Type
+}
+
+func (e *ErrSealed) Error() string {
+	return fmt.Sprintf("cannot change registration of %v: container is sealed", e.Type)
+}
+
+// Is reports whether target is an ErrSealed
+func (e *ErrSealed) Is(target error) bool {
+	_, ok := target.(*ErrSealed)
+	return ok
+}
+
+// ErrIndexOutOfRange is returned when an appended registration is resolved by an index that was never registered
+type ErrIndexOutOfRange struct {
+	Type  reflect.Type
+	Index int
+	Count int
+}
+
+func (e *ErrIndexOutOfRange) Error() string {
+	return fmt.Sprintf("index %d out of range for %v: %d registrations appended", e.Index, e.Type, e.Count)
+}
+
+// Is reports whether target is an ErrIndexOutOfRange
+func (e *ErrIndexOutOfRange) Is(target error) bool {
+	_, ok := target.(*ErrIndexOutOfRange)
+	return ok
+}
+
+// MultiError aggregates the failures of an operation that continues past errors
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the aggregated errors, which errors.Is and errors.As inspect from Go 1.20
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any aggregated error matches target, so errors.Is sees through a MultiError on Go 1.18
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first aggregated error matching target, so errors.As sees through a MultiError on Go 1.18
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return &MultiError{Errors: errs}
+	}
+}
+
+// isResolutionFailure reports whether err comes from resolving a dependency rather than from the code that
+// resolved it
+func isResolutionFailure(err error) bool {
+	return errors.Is(err, &ErrNotRegistered{}) ||
+		errors.Is(err, &ErrCircularDependency{}) ||
+		errors.Is(err, &ErrConstructorFailed{}) ||
+		errors.Is(err, &ErrConstructTimeout{}) ||
+		errors.Is(err, &ErrOutsideScope{}) ||
+		errors.Is(err, &ErrCaptiveDependency{}) ||
+		errors.Is(err, &ErrAmbiguousImplementation{})
+}
@@ -0,0 +1,93 @@
+package autowired_test
+
+import (
+	"errors"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+)
+
+// Test extracting the cycle path from a circular dependency error
+func TestCircularDependencyError(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[ServiceA](container, func(b *ServiceB) *ServiceA {
+		return &ServiceA{B: b}
+	})
+	_ = autowired.Register[ServiceB](container, func(a *ServiceA) *ServiceB {
+		return &ServiceB{A: a}
+	})
+
+	_, err := autowired.Resolve[*ServiceA](container)
+
+	var cycleErr *autowired.ErrCircularDependency
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected ErrCircularDependency, got %v", err)
+	}
+
+	expected := []reflect.Type{reflect.TypeOf(&ServiceA{}), reflect.TypeOf(&ServiceB{}), reflect.TypeOf(&ServiceA{})}
+	if !reflect.DeepEqual(cycleErr.Path, expected) {
+		t.Errorf("Expected cycle path %v, got %v", expected, cycleErr.Path)
+	}
+	if cycleErr.Error() != "circular dependency detected for type *autowired_test.ServiceA" {
+		t.Errorf("Unexpected error message: %s", cycleErr.Error())
+	}
+}
+
+// Test distinguishing failures with errors.Is and errors.As
+func TestTypedErrors(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[TestService](container, "not a function")
+	if !errors.Is(err, &autowired.ErrInvalidConstructor{}) {
+		t.Errorf("Expected ErrInvalidConstructor, got %v", err)
+	}
+
+	_, err = autowired.Resolve[*TestService](container)
+	if !errors.Is(err, &autowired.ErrNotRegistered{}) {
+		t.Errorf("Expected ErrNotRegistered, got %v", err)
+	}
+
+	constructorErr := errors.New("constructor error")
+	_ = autowired.Register[TestService](container, func() (*TestService, error) {
+		return nil, constructorErr
+	})
+
+	_, err = autowired.Resolve[*TestService](container)
+
+	var failed *autowired.ErrConstructorFailed
+	if !errors.As(err, &failed) {
+		t.Fatalf("Expected ErrConstructorFailed, got %v", err)
+	}
+	if failed.Type != reflect.TypeOf(&TestService{}) {
+		t.Errorf("Expected failed type *TestService, got %v", failed.Type)
+	}
+	if !errors.Is(err, constructorErr) {
+		t.Error("ErrConstructorFailed should unwrap to the constructor's error")
+	}
+	if err.Error() != "constructor error" {
+		t.Errorf("Expected original message 'constructor error', got '%s'", err.Error())
+	}
+}
+
+// Test a MultiError matches its aggregated errors by itself, without relying on Unwrap() []error
+func TestMultiErrorIsAs(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	multi := &autowired.MultiError{Errors: []error{
+		errTimeout,
+		&autowired.ErrNotRegistered{Type: reflect.TypeOf(&TestService{})},
+	}}
+
+	if !multi.Is(errTimeout) || multi.Is(errors.New("other")) {
+		t.Error("Expected Is to match only the aggregated errors")
+	}
+
+	var notRegistered *autowired.ErrNotRegistered
+	if !multi.As(&notRegistered) || notRegistered.Type != reflect.TypeOf(&TestService{}) {
+		t.Errorf("Expected As to find the ErrNotRegistered, got %v", notRegistered)
+	}
+	var cycle *autowired.ErrCircularDependency
+	if multi.As(&cycle) {
+		t.Error("Expected As not to find an ErrCircularDependency")
+	}
+}
@@ -0,0 +1,371 @@
+package autowired
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DependencyNode identifies a registration by its type and name
+type DependencyNode struct {
+	Type reflect.Type
+	Name string
+}
+
+func (n DependencyNode) String() string {
+	return fmt.Sprintf("%v (%s)", n.Type, n.Name)
+}
+
+// Registration describes a registered dependency
+type Registration struct {
+	Type  reflect.Type
+	Name  string
+	Scope Scope
+	Tags  []string
+}
+
+// RegisteredTypes returns every registered type and name pair, sorted by type and then name
+func (c *Container) RegisteredTypes() []DependencyNode {
+	infos := c.registrations()
+	nodes := make([]DependencyNode, len(infos))
+	for i, info := range infos {
+		nodes[i] = info.node()
+	}
+	return nodes
+}
+
+// Tagged returns the registrations labelled with tag, sorted by type and then name
+func (c *Container) Tagged(tag string) []DependencyNode {
+	var nodes []DependencyNode
+	for _, info := range c.registrations() {
+		for _, t := range info.tags {
+			if t == tag {
+				nodes = append(nodes, info.node())
+				break
+			}
+		}
+	}
+	return nodes
+}
+
+func (info *dependencyInfo) node() DependencyNode {
+	return DependencyNode{Type: info.typ, Name: info.name}
+}
+
+func (info *dependencyInfo) registration() Registration {
+	return Registration{Type: info.typ, Name: info.name, Scope: info.scope, Tags: append([]string(nil), info.tags...)}
+}
+
+// dependencies returns the nodes a registration depends on: those declared with SetDependencies, or else
+// those inferred from its constructor. Factories resolve their dependencies at runtime and have no static edges.
+func (info *dependencyInfo) dependencies() []DependencyNode {
+	if declared, ok := info.declared.Load().([]DependencyNode); ok {
+		return declared
+	}
+	if !info.constructor.IsValid() {
+		return nil
+	}
+
+	constructorType := info.constructor.Type()
+	var deps []DependencyNode
+	for i := 0; i < constructorType.NumIn(); i++ {
+		paramType := constructorType.In(i)
+		if paramType == contextType || paramType == resolutionNameType || isRegistry(paramType) || isProvider(paramType) {
+			continue
+		}
+		if isParamObject(paramType) {
+			deps = append(deps, paramObjectDependencies(paramType)...)
+			continue
+		}
+		if names, ok := info.collection(i); ok {
+			for _, name := range names {
+				deps = append(deps, DependencyNode{Type: paramType.Elem(), Name: name})
+			}
+			continue
+		}
+		name := info.qualifier(i)
+		if name == "" {
+			name = getDefaultName(paramType)
+		}
+		deps = append(deps, DependencyNode{Type: paramType, Name: name})
+	}
+	return deps
+}
+
+// dependenciesOf returns the dependencies of info as the registrations they resolve to, so an interface
+// parameter leads to the implementation, binding or embedding registration it is filled from. Dependencies
+// that do not resolve are kept as declared.
+func (c *Container) dependenciesOf(info *dependencyInfo) []DependencyNode {
+	deps := info.dependencies()
+	resolved := make([]DependencyNode, len(deps))
+	for i, dep := range deps {
+		resolved[i] = dep
+
+		// An unqualified dependency is resolved like a parameter without a name
+		name := dep.Name
+		if name == getDefaultName(dep.Type) {
+			name = ""
+		}
+		if target, _, err := c.lookup(dep.Type, name); err == nil {
+			resolved[i] = target.node()
+		}
+	}
+	return resolved
+}
+
+// Roots returns the registrations no other registration depends on, sorted by type and then name
+func (c *Container) Roots() []DependencyNode {
+	graph := c.graph()
+
+	dependedOn := make(map[DependencyNode]bool)
+	for _, deps := range graph {
+		for _, dep := range deps {
+			dependedOn[dep] = true
+		}
+	}
+
+	var roots []DependencyNode
+	for _, node := range c.RegisteredTypes() {
+		if !dependedOn[node] {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// Leaves returns the registrations that depend on nothing, sorted by type and then name.
+// Factories have no static edges, so they are leaves unless their dependencies are declared.
+func (c *Container) Leaves() []DependencyNode {
+	graph := c.graph()
+
+	var leaves []DependencyNode
+	for _, node := range c.RegisteredTypes() {
+		if len(graph[node]) == 0 {
+			leaves = append(leaves, node)
+		}
+	}
+	return leaves
+}
+
+// graph returns the dependency edges of every registration
+func (c *Container) graph() map[DependencyNode][]DependencyNode {
+	graph := make(map[DependencyNode][]DependencyNode)
+	for _, info := range c.registrations() {
+		graph[info.node()] = c.dependenciesOf(info)
+	}
+	return graph
+}
+
+// PathsBetween returns every dependency path from a registration of type from to a registration of type to
+func (c *Container) PathsBetween(from, to reflect.Type) [][]DependencyNode {
+	graph := c.graph()
+
+	var paths [][]DependencyNode
+	var walk func(node DependencyNode, path []DependencyNode)
+	walk = func(node DependencyNode, path []DependencyNode) {
+		for _, visited := range path {
+			if visited == node {
+				return
+			}
+		}
+
+		path = append(path, node)
+		if node.Type == to && len(path) > 1 {
+			paths = append(paths, append([]DependencyNode{}, path...))
+			return
+		}
+
+		for _, dep := range graph[node] {
+			walk(dep, path)
+		}
+	}
+
+	for _, node := range c.RegisteredTypes() {
+		if node.Type == from {
+			walk(node, nil)
+		}
+	}
+
+	return paths
+}
+
+// startLevels groups the singletons by phase, highest phase first, and within a phase into levels that only
+// depend on singletons in earlier levels. A singleton depending on one in a later phase builds it on demand.
+func (c *Container) startLevels() [][]*dependencyInfo {
+	singletons := c.singletons()
+	byNode := make(map[DependencyNode]*dependencyInfo, len(singletons))
+	for _, info := range singletons {
+		byNode[info.node()] = info
+	}
+
+	depth := make(map[DependencyNode]int)
+	visiting := make(map[DependencyNode]bool)
+	var levelOf func(info *dependencyInfo) int
+	levelOf = func(info *dependencyInfo) int {
+		node := info.node()
+		if d, ok := depth[node]; ok {
+			return d
+		}
+		// Cycles are reported when the singleton is resolved
+		if visiting[node] {
+			return 0
+		}
+		visiting[node] = true
+
+		d := 0
+		for _, dep := range c.dependenciesOf(info) {
+			if depInfo, ok := byNode[dep]; ok {
+				if l := levelOf(depInfo) + 1; l > d {
+					d = l
+				}
+			}
+		}
+
+		visiting[node] = false
+		depth[node] = d
+		return d
+	}
+
+	// Phases start in descending order, each one level by level
+	byPhase := make(map[int][][]*dependencyInfo)
+	var phases []int
+	for _, info := range singletons {
+		levels, seen := byPhase[info.phase]
+		if !seen {
+			phases = append(phases, info.phase)
+		}
+		d := levelOf(info)
+		for len(levels) <= d {
+			levels = append(levels, nil)
+		}
+		levels[d] = append(levels[d], info)
+		byPhase[info.phase] = levels
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(phases)))
+
+	var levels [][]*dependencyInfo
+	for _, phase := range phases {
+		for _, level := range byPhase[phase] {
+			if len(level) > 0 {
+				levels = append(levels, level)
+			}
+		}
+	}
+	return levels
+}
+
+// ExportDOT renders the dependency graph in Graphviz DOT format
+func (c *Container) ExportDOT() string {
+	return c.ExportDOTFiltered(nil)
+}
+
+// ExportDOTFiltered renders the registrations accepted by pred, and the edges between them, in Graphviz
+// DOT format. A nil pred accepts every registration. Edges to unregistered dependencies are omitted.
+func (c *Container) ExportDOTFiltered(pred func(Registration) bool) string {
+	included := make(map[DependencyNode]bool)
+	var infos []*dependencyInfo
+	for _, info := range c.registrations() {
+		if pred == nil || pred(info.registration()) {
+			included[info.node()] = true
+			infos = append(infos, info)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	for _, info := range infos {
+		fmt.Fprintf(&b, "  %q [label=%q];\n", info.node().String(), fmt.Sprintf("%v\n%s", info.node(), info.scope))
+	}
+	for _, info := range infos {
+		for _, dep := range c.dependenciesOf(info) {
+			if included[dep] {
+				fmt.Fprintf(&b, "  %q -> %q;\n", info.node().String(), dep.String())
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// DependencyEdge is a dependency of one registration on another
+type DependencyEdge struct {
+	From DependencyNode
+	To   DependencyNode
+}
+
+// GraphDiff lists the registrations and edges of container b missing from container a, and the reverse
+type GraphDiff struct {
+	AddedNodes   []DependencyNode
+	RemovedNodes []DependencyNode
+	AddedEdges   []DependencyEdge
+	RemovedEdges []DependencyEdge
+}
+
+// Empty reports whether both graphs are the same
+func (d GraphDiff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// DiffGraphs compares the dependency graphs of a and b. Added means present in b but not in a.
+func DiffGraphs(a, b *Container) GraphDiff {
+	nodesA, edgesA := a.RegisteredTypes(), a.edges()
+	nodesB, edgesB := b.RegisteredTypes(), b.edges()
+
+	return GraphDiff{
+		AddedNodes:   missingNodes(nodesB, nodesA),
+		RemovedNodes: missingNodes(nodesA, nodesB),
+		AddedEdges:   missingEdges(edgesB, edgesA),
+		RemovedEdges: missingEdges(edgesA, edgesB),
+	}
+}
+
+// edges returns every dependency edge, sorted by source and then target
+func (c *Container) edges() []DependencyEdge {
+	var edges []DependencyEdge
+	for from, deps := range c.graph() {
+		for _, to := range deps {
+			edges = append(edges, DependencyEdge{From: from, To: to})
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if fi, fj := edges[i].From.String(), edges[j].From.String(); fi != fj {
+			return fi < fj
+		}
+		return edges[i].To.String() < edges[j].To.String()
+	})
+	return edges
+}
+
+// missingNodes returns the nodes of from that are not in other
+func missingNodes(from, other []DependencyNode) []DependencyNode {
+	present := make(map[DependencyNode]bool, len(other))
+	for _, node := range other {
+		present[node] = true
+	}
+
+	var missing []DependencyNode
+	for _, node := range from {
+		if !present[node] {
+			missing = append(missing, node)
+		}
+	}
+	return missing
+}
+
+// missingEdges returns the edges of from that are not in other
+func missingEdges(from, other []DependencyEdge) []DependencyEdge {
+	present := make(map[DependencyEdge]bool, len(other))
+	for _, edge := range other {
+		present[edge] = true
+	}
+
+	var missing []DependencyEdge
+	for _, edge := range from {
+		if !present[edge] {
+			missing = append(missing, edge)
+		}
+	}
+	return missing
+}
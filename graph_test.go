@@ -0,0 +1,257 @@
+package autowired_test
+
+import (
+	"context"
+	"fmt"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test enumerating registrations
+func TestRegisteredTypes(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService, "secondary")
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	})
+
+	expected := []autowired.DependencyNode{
+		{Type: reflect.TypeOf(&RequestContext{}), Name: "requestContext"},
+		{Type: reflect.TypeOf(&TestService{}), Name: "secondary"},
+		{Type: reflect.TypeOf(&TestService{}), Name: "testService"},
+	}
+
+	if nodes := container.RegisteredTypes(); !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("Expected %v, got %v", expected, nodes)
+	}
+}
+
+type DiamondBase struct{}
+
+type DiamondLeft struct {
+	Base *DiamondBase
+}
+
+type DiamondRight struct {
+	Base *DiamondBase
+}
+
+type DiamondTop struct {
+	Left  *DiamondLeft
+	Right *DiamondRight
+}
+
+func registerDiamond(container *autowired.Container) {
+	_ = autowired.Register[DiamondBase](container, func() *DiamondBase { return &DiamondBase{} })
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft { return &DiamondLeft{Base: b} })
+	_ = autowired.Register[DiamondRight](container, func(b *DiamondBase) *DiamondRight { return &DiamondRight{Base: b} })
+	_ = autowired.Register[DiamondTop](container, func(l *DiamondLeft, r *DiamondRight) *DiamondTop {
+		return &DiamondTop{Left: l, Right: r}
+	})
+}
+
+// Test finding every path through a diamond-shaped graph
+func TestPathsBetween(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+
+	top := autowired.DependencyNode{Type: reflect.TypeOf(&DiamondTop{}), Name: "diamondTop"}
+	left := autowired.DependencyNode{Type: reflect.TypeOf(&DiamondLeft{}), Name: "diamondLeft"}
+	right := autowired.DependencyNode{Type: reflect.TypeOf(&DiamondRight{}), Name: "diamondRight"}
+	base := autowired.DependencyNode{Type: reflect.TypeOf(&DiamondBase{}), Name: "diamondBase"}
+
+	paths := container.PathsBetween(reflect.TypeOf(&DiamondTop{}), reflect.TypeOf(&DiamondBase{}))
+	expected := [][]autowired.DependencyNode{
+		{top, left, base},
+		{top, right, base},
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected paths %v, got %v", expected, paths)
+	}
+
+	if paths := container.PathsBetween(reflect.TypeOf(&DiamondBase{}), reflect.TypeOf(&DiamondTop{})); len(paths) != 0 {
+		t.Errorf("Expected no paths from DiamondBase to DiamondTop, got %v", paths)
+	}
+}
+
+// Test the DOT export can be filtered to singletons
+func TestExportDOTFiltered(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+	_ = autowired.Register[RequestContext](container, func(b *DiamondBase) *RequestContext {
+		return &RequestContext{}
+	}, autowired.Request)
+
+	dot := container.ExportDOTFiltered(func(r autowired.Registration) bool {
+		return r.Scope == autowired.Singleton
+	})
+
+	if !strings.HasPrefix(dot, "digraph dependencies {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("Expected a DOT digraph, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"*autowired_test.DiamondTop (diamondTop)" -> "*autowired_test.DiamondLeft (diamondLeft)";`) {
+		t.Errorf("Expected the singleton edges to be exported, got:\n%s", dot)
+	}
+	if strings.Contains(dot, "RequestContext") {
+		t.Errorf("Expected request-scoped registrations to be filtered out, got:\n%s", dot)
+	}
+	if !strings.Contains(container.ExportDOT(), "RequestContext") {
+		t.Error("Expected the unfiltered export to include every registration")
+	}
+}
+
+// Test declared dependencies replace the edges inferred from the constructor
+func TestSetDependencies(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+
+	left := autowired.DependencyNode{Type: reflect.TypeOf(&DiamondLeft{}), Name: "diamondLeft"}
+	base := autowired.DependencyNode{Type: reflect.TypeOf(&DiamondBase{}), Name: "diamondBase"}
+	top := autowired.DependencyNode{Type: reflect.TypeOf(&DiamondTop{}), Name: "diamondTop"}
+
+	// DiamondTop's constructor takes both branches, but declare that it only needs the left one
+	if err := container.SetDependencies(top.Type, "", left); err != nil {
+		t.Fatalf("Failed to set dependencies: %v", err)
+	}
+
+	paths := container.PathsBetween(top.Type, base.Type)
+	expected := [][]autowired.DependencyNode{{top, left, base}}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected paths %v, got %v", expected, paths)
+	}
+
+	missing := autowired.DependencyNode{Type: reflect.TypeOf(&RequestContext{}), Name: "requestContext"}
+	if err := container.SetDependencies(top.Type, "", left, missing); err != nil {
+		t.Fatalf("Failed to set dependencies: %v", err)
+	}
+	if err := container.Validate(); !strings.Contains(fmt.Sprint(err), "declared dependency *autowired_test.RequestContext (requestContext)") {
+		t.Errorf("Expected Validate to report the unregistered declared dependency, got %v", err)
+	}
+
+	if err := container.SetDependencies(missing.Type, ""); err == nil {
+		t.Error("Expected error when declaring dependencies of an unregistered type, got nil")
+	}
+}
+
+// Test roots are the registrations nothing depends on
+func TestRoots(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+	_ = autowired.Register[RequestContext](container, func(l *DiamondLeft) *RequestContext {
+		return &RequestContext{}
+	})
+
+	expected := []autowired.DependencyNode{
+		{Type: reflect.TypeOf(&DiamondTop{}), Name: "diamondTop"},
+		{Type: reflect.TypeOf(&RequestContext{}), Name: "requestContext"},
+	}
+	if roots := container.Roots(); !reflect.DeepEqual(roots, expected) {
+		t.Errorf("Expected roots %v, got %v", expected, roots)
+	}
+}
+
+// Test leaves are the registrations without dependencies
+func TestLeaves(t *testing.T) {
+	container := autowired.NewContainer()
+	registerDiamond(container)
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	})
+
+	expected := []autowired.DependencyNode{
+		{Type: reflect.TypeOf(&DiamondBase{}), Name: "diamondBase"},
+		{Type: reflect.TypeOf(&RequestContext{}), Name: "requestContext"},
+	}
+	if leaves := container.Leaves(); !reflect.DeepEqual(leaves, expected) {
+		t.Errorf("Expected leaves %v, got %v", expected, leaves)
+	}
+}
+
+// Test diffing a graph against one with an extra registration
+func TestDiffGraphs(t *testing.T) {
+	before := autowired.NewContainer()
+	registerDiamond(before)
+
+	after := autowired.NewContainer()
+	registerDiamond(after)
+	_ = autowired.Register[RequestContext](after, func(top *DiamondTop) *RequestContext {
+		return &RequestContext{}
+	})
+
+	if diff := autowired.DiffGraphs(before, before); !diff.Empty() {
+		t.Errorf("Expected no differences between a graph and itself, got %+v", diff)
+	}
+
+	request := autowired.DependencyNode{Type: reflect.TypeOf(&RequestContext{}), Name: "requestContext"}
+	top := autowired.DependencyNode{Type: reflect.TypeOf(&DiamondTop{}), Name: "diamondTop"}
+
+	diff := autowired.DiffGraphs(before, after)
+	expected := autowired.GraphDiff{
+		AddedNodes: []autowired.DependencyNode{request},
+		AddedEdges: []autowired.DependencyEdge{{From: request, To: top}},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, diff)
+	}
+
+	reverse := autowired.DiffGraphs(after, before)
+	if !reflect.DeepEqual(reverse.RemovedNodes, expected.AddedNodes) || !reflect.DeepEqual(reverse.RemovedEdges, expected.AddedEdges) {
+		t.Errorf("Expected the reverse diff to report removals, got %+v", reverse)
+	}
+}
+
+type GraphStore interface {
+	Get(key string) string
+}
+
+type GraphDB struct{}
+
+func (d *GraphDB) Get(key string) string { return key }
+
+type GraphServer struct {
+	Store GraphStore
+}
+
+// Test an interface dependency leads to the registration implementing it
+func TestInterfaceDependencyEdges(t *testing.T) {
+	container := autowired.NewContainer()
+
+	var destroyed []string
+	_ = autowired.Register[GraphServer](container, func(s GraphStore) *GraphServer {
+		return &GraphServer{Store: s}
+	}, autowired.LifecycleHooks[*GraphServer]{OnDestroy: func(*GraphServer) error {
+		destroyed = append(destroyed, "server")
+		return nil
+	}})
+	_ = autowired.Register[GraphDB](container, func() *GraphDB {
+		return &GraphDB{}
+	}, autowired.LifecycleHooks[*GraphDB]{OnDestroy: func(*GraphDB) error {
+		destroyed = append(destroyed, "db")
+		return nil
+	}})
+
+	server := autowired.DependencyNode{Type: reflect.TypeOf(&GraphServer{}), Name: "graphServer"}
+	db := autowired.DependencyNode{Type: reflect.TypeOf(&GraphDB{}), Name: "graphDB"}
+
+	if roots := container.Roots(); !reflect.DeepEqual(roots, []autowired.DependencyNode{server}) {
+		t.Errorf("Expected roots %v, got %v", []autowired.DependencyNode{server}, roots)
+	}
+	expected := [][]autowired.DependencyNode{{server, db}}
+	if paths := container.PathsBetween(server.Type, db.Type); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected paths %v, got %v", expected, paths)
+	}
+
+	if err := container.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start container: %v", err)
+	}
+	if err := container.Destroy(); err != nil {
+		t.Fatalf("Failed to destroy container: %v", err)
+	}
+	if !reflect.DeepEqual(destroyed, []string{"server", "db"}) {
+		t.Errorf("Expected the server destroyed before the db, got %v", destroyed)
+	}
+}
@@ -0,0 +1,37 @@
+package autowired
+
+import (
+	"context"
+	"fmt"
+)
+
+// HealthChecker is implemented by components that can report whether they are healthy
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthCheck runs the check of every started singleton implementing HealthChecker, in dependency order, and
+// returns the failures together. Singletons that have not been constructed are not checked.
+func (c *Container) HealthCheck(ctx context.Context) error {
+	var errs []error
+	for _, level := range c.startLevels() {
+		for _, info := range level {
+			instance, started := info.cached()
+			if !started {
+				continue
+			}
+			checker, ok := instance.(HealthChecker)
+			if !ok {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := checker.HealthCheck(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("%v is unhealthy: %w", info.node(), err))
+			}
+		}
+	}
+	return joinErrors(errs)
+}
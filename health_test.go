@@ -0,0 +1,44 @@
+package autowired_test
+
+import (
+	"context"
+	"errors"
+	"me.sithiramunasinghe/go-autowired"
+	"strings"
+	"testing"
+)
+
+var errUnreachable = errors.New("unreachable")
+
+type Probe struct {
+	Err error
+}
+
+func (p *Probe) HealthCheck(context.Context) error {
+	return p.Err
+}
+
+// Test HealthCheck aggregates the failures of started singletons and skips everything else
+func TestHealthCheck(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[Probe](container, func() *Probe { return &Probe{} }, "cache")
+	_ = autowired.Register[Probe](container, func() *Probe { return &Probe{Err: errUnreachable} }, "database")
+	_ = autowired.Register[TestService](container, NewTestService)
+
+	if err := container.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("Expected no failures before anything is started, got %v", err)
+	}
+
+	if err := container.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start container: %v", err)
+	}
+
+	err := container.HealthCheck(context.Background())
+	if !errors.Is(err, errUnreachable) {
+		t.Fatalf("Expected the database failure, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "database") || strings.Contains(err.Error(), "cache") {
+		t.Errorf("Expected only the database to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package autowired
+
+import "reflect"
+
+var loggerType = reflect.TypeOf((*Logger)(nil)).Elem()
+
+// Logger receives the container's diagnostic messages
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+
+func (nopLogger) Warnf(string, ...interface{}) {}
+
+// loggerBox holds the container's logger, so that loggers of any type, nil included, fit in one atomic.Value
+type loggerBox struct {
+	logger Logger
+}
+
+// SetLogger sets the logger for the container's diagnostics. A nil logger discards them.
+func (c *Container) SetLogger(logger Logger) {
+	c.log.Store(loggerBox{logger: logger})
+}
+
+// logger returns the container's logger without locking, since every construction asks for it
+func (c *Container) logger() Logger {
+	box, _ := c.log.Load().(loggerBox)
+	if box.logger == nil {
+		return nopLogger{}
+	}
+	return box.logger
+}
+
+// SetLoggerFactory makes constructors with a Logger parameter receive factory(ownerType), where ownerType is
+// the type the constructor registers, instead of a Logger resolved from the container. A nil factory restores
+// normal resolution.
+func (c *Container) SetLoggerFactory(factory func(ownerType reflect.Type) Logger) {
+	c.loggerFactory.Store(factory)
+}
+
+// loggerFactoryFunc returns the factory set with SetLoggerFactory, or nil
+func (c *Container) loggerFactoryFunc() func(ownerType reflect.Type) Logger {
+	factory, _ := c.loggerFactory.Load().(func(ownerType reflect.Type) Logger)
+	return factory
+}
+
+// ownerLogger returns the logger built for the owner of info, if a logger factory is set
+func (c *Container) ownerLogger(info *dependencyInfo) (Logger, bool) {
+	if info == nil {
+		return nil, false
+	}
+
+	factory := c.loggerFactoryFunc()
+	if factory == nil {
+		return nil, false
+	}
+	return factory(info.typ), true
+}
@@ -0,0 +1,256 @@
+package autowired_test
+
+import (
+	"fmt"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// capturingLogger records every message logged by the container
+type capturingLogger struct {
+	mu       sync.Mutex
+	debug    []string
+	warnings []string
+}
+
+func (l *capturingLogger) Debugf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debug = append(l.debug, fmt.Sprintf(format, args...))
+}
+
+func (l *capturingLogger) Warnf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
+
+// Test deprecated registrations warn once on resolution
+func TestDeprecate(t *testing.T) {
+	container := autowired.NewContainer()
+	logger := &capturingLogger{}
+	container.SetLogger(logger)
+
+	_ = autowired.Register[TestService](container, NewTestService, autowired.Prototype)
+
+	err := container.Deprecate(reflect.TypeOf(&TestService{}), "", "use GreeterService instead")
+	if err != nil {
+		t.Fatalf("Failed to deprecate TestService: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := autowired.Resolve[*TestService](container); err != nil {
+			t.Fatalf("Failed to resolve deprecated TestService: %v", err)
+		}
+	}
+
+	if len(logger.warnings) != 1 {
+		t.Fatalf("Expected one deprecation warning, got %v", logger.warnings)
+	}
+	if !strings.Contains(logger.warnings[0], "use GreeterService instead") {
+		t.Errorf("Expected warning to point to the replacement, got '%s'", logger.warnings[0])
+	}
+
+	if err := container.Deprecate(reflect.TypeOf(&RequestContext{}), "", "gone"); err == nil {
+		t.Error("Expected error when deprecating an unregistered type, got nil")
+	}
+}
+
+// ownerLogger is a Logger tagged with the component it was built for
+type ownerLogger struct {
+	capturingLogger
+	owner string
+}
+
+type auditService struct {
+	Log autowired.Logger
+}
+
+type billingService struct {
+	Log autowired.Logger
+}
+
+// Test each component receives a logger built for its own type
+func TestSetLoggerFactory(t *testing.T) {
+	container := autowired.NewContainer()
+	container.SetLoggerFactory(func(ownerType reflect.Type) autowired.Logger {
+		return &ownerLogger{owner: ownerType.String()}
+	})
+
+	_ = autowired.Register[auditService](container, func(log autowired.Logger) *auditService {
+		return &auditService{Log: log}
+	})
+	_ = autowired.Register[billingService](container, func(log autowired.Logger) *billingService {
+		return &billingService{Log: log}
+	})
+
+	audit, err := autowired.Resolve[*auditService](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve auditService: %v", err)
+	}
+	billing, err := autowired.Resolve[*billingService](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve billingService: %v", err)
+	}
+
+	if owner := audit.Log.(*ownerLogger).owner; owner != "*autowired_test.auditService" {
+		t.Errorf("Expected audit logger tagged with its type, got '%s'", owner)
+	}
+	if owner := billing.Log.(*ownerLogger).owner; owner != "*autowired_test.billingService" {
+		t.Errorf("Expected billing logger tagged with its type, got '%s'", owner)
+	}
+}
+
+// Test constructions slower than the threshold are reported
+func TestSlowThreshold(t *testing.T) {
+	container := autowired.NewContainer()
+	logger := &capturingLogger{}
+	container.SetLogger(logger)
+	container.SlowThreshold = 5 * time.Millisecond
+
+	_ = autowired.Register[SlowService](container, func() *SlowService {
+		time.Sleep(10 * time.Millisecond)
+		return &SlowService{}
+	})
+	_ = autowired.Register[TestService](container, NewTestService)
+
+	if _, err := autowired.Resolve[*SlowService](container); err != nil {
+		t.Fatalf("Failed to resolve SlowService: %v", err)
+	}
+	if _, err := autowired.Resolve[*TestService](container); err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+
+	if len(logger.warnings) != 1 || !strings.Contains(logger.warnings[0], "slow construction of *autowired_test.SlowService (slowService)") {
+		t.Errorf("Expected one slow construction warning for SlowService, got %v", logger.warnings)
+	}
+}
+
+// Test dangling constructor parameters warn once per missing type, while forward references stay quiet
+func TestWarnDangling(t *testing.T) {
+	container := autowired.NewContainer()
+	container.WarnDangling = true
+	logger := &capturingLogger{}
+	container.SetLogger(logger)
+
+	_ = autowired.Register[DiamondTop](container, func(l *DiamondLeft, r *DiamondRight) *DiamondTop {
+		return &DiamondTop{Left: l, Right: r}
+	})
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft { return &DiamondLeft{Base: b} })
+	_ = autowired.Register[DiamondRight](container, func(b *DiamondBase) *DiamondRight { return &DiamondRight{Base: b} })
+
+	for i := 0; i < 2; i++ {
+		if err := container.Validate(); err == nil {
+			t.Fatal("Expected Validate to report the missing DiamondBase, got nil")
+		}
+	}
+
+	if len(logger.warnings) != 1 || !strings.Contains(logger.warnings[0], "DiamondBase") {
+		t.Errorf("Expected a single warning about DiamondBase, got %v", logger.warnings)
+	}
+}
+
+// Test injecting the zero value of a missing defaultable parameter is logged at debug level
+func TestDefaultableParameterLogged(t *testing.T) {
+	container := autowired.NewContainer()
+	logger := &capturingLogger{}
+	container.SetLogger(logger)
+
+	autowired.RegisterDefaultable[*OptionalConfig](container)
+	_ = autowired.Register[ConfiguredService](container, func(config *OptionalConfig) *ConfiguredService {
+		return &ConfiguredService{Config: config}
+	})
+
+	if _, err := autowired.Resolve[*ConfiguredService](container); err != nil {
+		t.Fatalf("Failed to resolve ConfiguredService: %v", err)
+	}
+
+	var zeroFilled []string
+	for _, message := range logger.debug {
+		if strings.Contains(message, "zero value") {
+			zeroFilled = append(zeroFilled, message)
+		}
+	}
+	if len(zeroFilled) != 1 {
+		t.Fatalf("Expected one zero value message, got %v", logger.debug)
+	}
+	if message := zeroFilled[0]; !strings.Contains(message, "ConfiguredService") || !strings.Contains(message, "*autowired_test.OptionalConfig") {
+		t.Errorf("Expected the message to name the owner and the missing type, got '%s'", message)
+	}
+}
+
+// Test constructor arguments are logged with their index and nil-ness before the constructor runs
+func TestConstructorArgumentsLogged(t *testing.T) {
+	container := autowired.NewContainer()
+	logger := &capturingLogger{}
+	container.SetLogger(logger)
+
+	autowired.RegisterDefaultable[*OptionalConfig](container)
+	_ = autowired.Register[Reporter](container, func(primary *TestService, config *OptionalConfig) *Reporter {
+		return &Reporter{Primary: primary}
+	})
+	_ = autowired.Register[TestService](container, NewTestService)
+
+	if _, err := autowired.Resolve[*Reporter](container); err != nil {
+		t.Fatalf("Failed to resolve Reporter: %v", err)
+	}
+
+	expected := []string{
+		"constructing *autowired_test.Reporter (reporter): argument 0 of type *autowired_test.TestService is set",
+		"constructing *autowired_test.Reporter (reporter): argument 1 of type *autowired_test.OptionalConfig is nil",
+	}
+	for _, message := range expected {
+		found := false
+		for _, logged := range logger.debug {
+			found = found || logged == message
+		}
+		if !found {
+			t.Errorf("Expected debug message '%s', got %v", message, logger.debug)
+		}
+	}
+}
+
+// Test the logger can be swapped while a sealed container constructs prototypes
+func TestSetLoggerWhileResolving(t *testing.T) {
+	container := autowired.NewContainer()
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[RequestContext](container, func(s *TestService) *RequestContext {
+		return &RequestContext{}
+	}, autowired.Prototype)
+	container.Seal()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := autowired.Resolve[*RequestContext](container); err != nil {
+					t.Errorf("Failed to resolve RequestContext: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	logger := &capturingLogger{}
+	for i := 0; i < 100; i++ {
+		container.SetLogger(logger)
+		container.SetLogger(nil)
+	}
+	container.SetLogger(logger)
+	wg.Wait()
+
+	if _, err := autowired.Resolve[*RequestContext](container); err != nil {
+		t.Fatalf("Failed to resolve RequestContext: %v", err)
+	}
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if len(logger.debug) == 0 {
+		t.Error("Expected constructions to log through the logger set last")
+	}
+}
@@ -0,0 +1,61 @@
+package autowired
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ResolveMeta describes what one resolution did
+type ResolveMeta struct {
+	// CacheHit is true when nothing had to be constructed
+	CacheHit bool
+	// Lifetime is the scope of the registration resolved
+	Lifetime Scope
+	// Instantiated counts the instances constructed, the resolved one and its dependencies alike
+	Instantiated int
+	// Duration is the time the resolution took
+	Duration time.Duration
+}
+
+type resolveMetaKey struct{}
+
+// resolveRecorder collects the ResolveMeta of a resolution from the context it runs with
+type resolveRecorder struct {
+	root         sync.Once
+	lifetime     Scope
+	instantiated int64
+}
+
+func recorderOf(ctx context.Context) *resolveRecorder {
+	recorder, _ := ctx.Value(resolveMetaKey{}).(*resolveRecorder)
+	return recorder
+}
+
+// resolving notes the registration being resolved, the first of which is the one asked for
+func (r *resolveRecorder) resolving(info *dependencyInfo) {
+	r.root.Do(func() { r.lifetime = info.scope })
+}
+
+func (r *resolveRecorder) constructed() {
+	atomic.AddInt64(&r.instantiated, 1)
+}
+
+// ResolveWithMeta is like ResolveContext but also reports whether the instance came from a cache, its lifetime,
+// how many instances had to be constructed and how long it took
+func (c *Container) ResolveWithMeta(ctx context.Context, typ reflect.Type, options ...interface{}) (interface{}, ResolveMeta, error) {
+	recorder := &resolveRecorder{}
+	start := time.Now()
+	instance, err := c.ResolveContext(context.WithValue(ctx, resolveMetaKey{}, recorder), typ, options...)
+
+	instantiated := int(atomic.LoadInt64(&recorder.instantiated))
+	meta := ResolveMeta{
+		CacheHit:     err == nil && instantiated == 0,
+		Lifetime:     recorder.lifetime,
+		Instantiated: instantiated,
+		Duration:     time.Since(start),
+	}
+	return instance, meta, err
+}
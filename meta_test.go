@@ -0,0 +1,44 @@
+package autowired_test
+
+import (
+	"context"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+)
+
+// Test ResolveWithMeta reports a miss and then a cache hit for a singleton
+func TestResolveWithMeta(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[DiamondBase](container, func() *DiamondBase { return &DiamondBase{} })
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft {
+		return &DiamondLeft{Base: b}
+	}, autowired.Prototype)
+
+	typ := reflect.TypeOf(&TestService{})
+	_, meta, err := container.ResolveWithMeta(context.Background(), typ)
+	if err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+	if meta.CacheHit || meta.Instantiated != 1 || meta.Lifetime != autowired.Singleton {
+		t.Errorf("Expected a singleton miss instantiating 1 instance, got %+v", meta)
+	}
+
+	_, meta, err = container.ResolveWithMeta(context.Background(), typ)
+	if err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+	if !meta.CacheHit || meta.Instantiated != 0 {
+		t.Errorf("Expected a cache hit on the second resolve, got %+v", meta)
+	}
+
+	_, meta, err = container.ResolveWithMeta(context.Background(), reflect.TypeOf(&DiamondLeft{}))
+	if err != nil {
+		t.Fatalf("Failed to resolve DiamondLeft: %v", err)
+	}
+	if meta.CacheHit || meta.Instantiated != 2 || meta.Lifetime != autowired.Prototype {
+		t.Errorf("Expected a prototype instantiating itself and its dependency, got %+v", meta)
+	}
+}
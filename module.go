@@ -0,0 +1,28 @@
+package autowired
+
+import "fmt"
+
+// Module groups related registrations into a unit that can be installed into a container
+type Module interface {
+	Register(c *Container) error
+}
+
+// ModuleFunc adapts a function to a Module
+type ModuleFunc func(c *Container) error
+
+// Register calls f(c)
+func (f ModuleFunc) Register(c *Container) error {
+	return f(c)
+}
+
+// Install registers each module in order, so later modules may rely on the registrations of earlier ones.
+// Every module is installed even if an earlier one fails; the failures are returned together.
+func (c *Container) Install(modules ...Module) error {
+	var errs []error
+	for _, module := range modules {
+		if err := module.Register(c); err != nil {
+			errs = append(errs, fmt.Errorf("failed to install module %T: %w", module, err))
+		}
+	}
+	return joinErrors(errs)
+}
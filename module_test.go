@@ -0,0 +1,58 @@
+package autowired_test
+
+import (
+	"errors"
+	"me.sithiramunasinghe/go-autowired"
+	"testing"
+)
+
+type storageModule struct{}
+
+func (storageModule) Register(c *autowired.Container) error {
+	return autowired.Register[TestService](c, NewTestService)
+}
+
+type reportingModule struct{}
+
+func (reportingModule) Register(c *autowired.Container) error {
+	return autowired.Register[Reporter](c, func(s *TestService) *Reporter {
+		return &Reporter{Primary: s}
+	})
+}
+
+// Test a module can depend on the registrations of an earlier module
+func TestInstall(t *testing.T) {
+	container := autowired.NewContainer()
+
+	if err := container.Install(storageModule{}, reportingModule{}); err != nil {
+		t.Fatalf("Failed to install modules: %v", err)
+	}
+
+	reporter, err := autowired.Resolve[*Reporter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Reporter: %v", err)
+	}
+	if reporter.Primary == nil || reporter.Primary.Value != "default" {
+		t.Errorf("Expected Reporter to receive the TestService from the storage module, got %+v", reporter.Primary)
+	}
+}
+
+// Test module failures are aggregated without stopping later modules
+func TestInstallAggregatesErrors(t *testing.T) {
+	container := autowired.NewContainer()
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	err := container.Install(
+		autowired.ModuleFunc(func(c *autowired.Container) error { return errFirst }),
+		storageModule{},
+		autowired.ModuleFunc(func(c *autowired.Container) error { return errSecond }),
+	)
+
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("Expected both module errors, got %v", err)
+	}
+	if _, err := autowired.Resolve[*TestService](container); err != nil {
+		t.Errorf("Expected modules after a failure to be installed: %v", err)
+	}
+}
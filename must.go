@@ -0,0 +1,118 @@
+package autowired
+
+import (
+	"context"
+	"time"
+)
+
+// must panics with err, so wiring mistakes in init code fail loudly instead of being discarded
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// MustRegister is like Register but panics if the registration fails
+func MustRegister[T any](c *Container, constructor interface{}, options ...interface{}) {
+	must(Register[T](c, constructor, options...))
+}
+
+// MustRegisterSingleton registers T as a singleton and panics if the registration fails
+func MustRegisterSingleton[T any](c *Container, constructor interface{}, options ...interface{}) {
+	must(Register[T](c, constructor, append(options, Singleton)...))
+}
+
+// MustRegisterPrototype registers T as a prototype and panics if the registration fails
+func MustRegisterPrototype[T any](c *Container, constructor interface{}, options ...interface{}) {
+	must(Register[T](c, constructor, append(options, Prototype)...))
+}
+
+// MustRegisterScoped registers T as request-scoped and panics if the registration fails
+func MustRegisterScoped[T any](c *Container, constructor interface{}, options ...interface{}) {
+	must(Register[T](c, constructor, append(options, Request)...))
+}
+
+// MustRegisterDefault is like RegisterDefault but panics if the registration fails
+func MustRegisterDefault[T any](c *Container, constructor interface{}, options ...interface{}) {
+	must(RegisterDefault[T](c, constructor, options...))
+}
+
+// MustRegisterAppend is like RegisterAppend but panics if the registration fails
+func MustRegisterAppend[T any](c *Container, constructor interface{}, options ...interface{}) {
+	must(RegisterAppend[T](c, constructor, options...))
+}
+
+// MustRegisterFuncSingleton is like RegisterFuncSingleton but panics if the registration fails
+func MustRegisterFuncSingleton[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), options ...interface{}) {
+	must(RegisterFuncSingleton[T](c, fn, options...))
+}
+
+// MustRegisterFuncPrototype is like RegisterFuncPrototype but panics if the registration fails
+func MustRegisterFuncPrototype[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), options ...interface{}) {
+	must(RegisterFuncPrototype[T](c, fn, options...))
+}
+
+// MustRegisterFuncRequest is like RegisterFuncRequest but panics if the registration fails
+func MustRegisterFuncRequest[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), options ...interface{}) {
+	must(RegisterFuncRequest[T](c, fn, options...))
+}
+
+// MustRegisterFactoryFunc is like RegisterFactoryFunc but panics if the registration fails
+func MustRegisterFactoryFunc[T any](c *Container, fn interface{}, options ...interface{}) {
+	must(RegisterFactoryFunc[T](c, fn, options...))
+}
+
+// MustRegisterSingletonFast is like RegisterSingletonFast but panics if the registration fails
+func MustRegisterSingletonFast[T any](c *Container, constructor interface{}, adapter func(*Container, context.Context) (T, error), options ...interface{}) {
+	must(RegisterSingletonFast[T](c, constructor, adapter, options...))
+}
+
+// MustRegisterQualified is like RegisterQualified but panics if the registration fails
+func MustRegisterQualified[T any](c *Container, constructor interface{}, qualifiers map[int]string, options ...interface{}) {
+	must(RegisterQualified[T](c, constructor, qualifiers, options...))
+}
+
+// MustRegisterSingletonWithPhase is like RegisterSingletonWithPhase but panics if the registration fails
+func MustRegisterSingletonWithPhase[T any](c *Container, constructor interface{}, hooks LifecycleHooks[*T], phase int, options ...interface{}) {
+	must(RegisterSingletonWithPhase[T](c, constructor, hooks, phase, options...))
+}
+
+// MustRegisterSingletonTTL is like RegisterSingletonTTL but panics if the registration fails
+func MustRegisterSingletonTTL[T any](c *Container, constructor interface{}, ttl time.Duration, options ...interface{}) {
+	must(RegisterSingletonTTL[T](c, constructor, ttl, options...))
+}
+
+// MustRegisterWithEmbedded is like RegisterWithEmbedded but panics if the registration fails
+func MustRegisterWithEmbedded[T any](c *Container, constructor interface{}, options ...interface{}) {
+	must(RegisterWithEmbedded[T](c, constructor, options...))
+}
+
+// MustRegisterSingletonFactoryWithHooks is like RegisterSingletonFactoryWithHooks but panics if the registration fails
+func MustRegisterSingletonFactoryWithHooks[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), hooks LifecycleHooks[T], options ...interface{}) {
+	must(RegisterSingletonFactoryWithHooks[T](c, fn, hooks, options...))
+}
+
+// MustRegisterPrototypeFactoryWithHooks is like RegisterPrototypeFactoryWithHooks but panics if the registration fails
+func MustRegisterPrototypeFactoryWithHooks[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), hooks LifecycleHooks[T], options ...interface{}) {
+	must(RegisterPrototypeFactoryWithHooks[T](c, fn, hooks, options...))
+}
+
+// MustRegisterRequestFactoryWithHooks is like RegisterRequestFactoryWithHooks but panics if the registration fails
+func MustRegisterRequestFactoryWithHooks[T any](c *Container, fn func(ctx context.Context, c *Container) (T, error), hooks LifecycleHooks[T], options ...interface{}) {
+	must(RegisterRequestFactoryWithHooks[T](c, fn, hooks, options...))
+}
+
+// MustRegisterShared is like RegisterShared but panics if the registration fails
+func MustRegisterShared[T any](c *Container, key string, constructor interface{}, options ...interface{}) {
+	must(RegisterShared[T](c, key, constructor, options...))
+}
+
+// MustRegisterWeakSingleton is like RegisterWeakSingleton but panics if the registration fails
+func MustRegisterWeakSingleton[T any](c *Container, constructor interface{}, options ...interface{}) {
+	must(RegisterWeakSingleton[T](c, constructor, options...))
+}
+
+// MustRegisterForPlatform is like RegisterForPlatform but panics if the registration fails
+func MustRegisterForPlatform[T any](c *Container, goos, goarch string, constructor interface{}, options ...interface{}) {
+	must(RegisterForPlatform[T](c, goos, goarch, constructor, options...))
+}
@@ -0,0 +1,54 @@
+package autowired_test
+
+import (
+	"errors"
+	"io"
+	"me.sithiramunasinghe/go-autowired"
+	"testing"
+	"time"
+)
+
+// Test Must variants register normally and panic on a mismatched constructor
+func TestMustRegister(t *testing.T) {
+	container := autowired.NewContainer()
+
+	autowired.MustRegisterSingleton[TestService](container, NewTestService)
+	autowired.MustRegisterScoped[RequestContext](container, func() *RequestContext { return &RequestContext{} })
+
+	if _, err := autowired.Resolve[*TestService](container); err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, &autowired.ErrInvalidConstructor{}) {
+			t.Errorf("Expected a panic with ErrInvalidConstructor, got %v", err)
+		}
+	}()
+	autowired.MustRegisterSingleton[RequestContext](container, NewTestService)
+	t.Error("Expected MustRegisterSingleton to panic for a mismatched constructor")
+}
+
+// Test the Must variants of the specialised helpers register normally and panic on failure
+func TestMustRegisterVariants(t *testing.T) {
+	container := autowired.NewContainer()
+
+	autowired.MustRegisterWithEmbedded[EmbeddedResource](container, func() *EmbeddedResource {
+		return &EmbeddedResource{Closer: closerStub{}}
+	})
+	autowired.MustRegisterSingletonTTL[TestService](container, NewTestService, time.Minute)
+
+	if _, err := autowired.Resolve[io.Closer](container); err != nil {
+		t.Fatalf("Failed to resolve io.Closer: %v", err)
+	}
+	if _, err := autowired.Resolve[*TestService](container); err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustRegisterSingletonTTL to panic for a zero ttl")
+		}
+	}()
+	autowired.MustRegisterSingletonTTL[TestService](container, NewTestService, 0)
+}
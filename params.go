@@ -0,0 +1,98 @@
+package autowired
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// In marks a parameter object. A constructor parameter whose struct type embeds In is built by resolving each of
+// its exported fields, with an autowire tag naming the registration to use and "-" skipping the field. Other
+// embedded structs are parameter groups whose fields are resolved the same way, recursively, so a set of
+// dependencies can be shared between parameter objects.
+type In struct{}
+
+var inType = reflect.TypeOf(In{})
+
+// isParamObject reports whether typ is a struct embedding In
+func isParamObject(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Anonymous && field.Type == inType {
+			return true
+		}
+	}
+	return false
+}
+
+// paramField is a field of a parameter object to resolve, with Index leading from the parameter object to it
+type paramField struct {
+	reflect.StructField
+	name string
+}
+
+// paramFields lists the fields of the parameter object typ to resolve, descending into embedded groups
+func paramFields(typ reflect.Type) []paramField {
+	var fields []paramField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := field.Tag.Get("autowire")
+		if field.Type == inType || name == "-" {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, nested := range paramFields(field.Type) {
+				nested.Index = append([]int{i}, nested.Index...)
+				fields = append(fields, nested)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		fields = append(fields, paramField{StructField: field, name: name})
+	}
+	return fields
+}
+
+// resolveParamObject builds the parameter object of type typ for a constructor of info
+func (c *Container) resolveParamObject(ctx context.Context, info *dependencyInfo, typ reflect.Type) (reflect.Value, error) {
+	object := reflect.New(typ).Elem()
+	for _, field := range paramFields(typ) {
+		if c.Strict && info != nil && info.scope == Singleton {
+			if err := c.checkCaptive(ctx, info, field.Type, field.name); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+
+		var options []interface{}
+		if field.name != "" {
+			options = append(options, field.name)
+		}
+
+		dependency, err := c.ResolveContext(ctx, field.Type, options...)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to resolve field %s of %v: %w", field.Name, typ, err)
+		}
+		if dependency != nil {
+			object.FieldByIndex(field.Index).Set(reflect.ValueOf(dependency))
+		}
+	}
+	return object, nil
+}
+
+// paramObjectDependencies returns the registrations the fields of the parameter object typ resolve to
+func paramObjectDependencies(typ reflect.Type) []DependencyNode {
+	var deps []DependencyNode
+	for _, field := range paramFields(typ) {
+		name := field.name
+		if name == "" {
+			name = getDefaultName(field.Type)
+		}
+		deps = append(deps, DependencyNode{Type: field.Type, Name: name})
+	}
+	return deps
+}
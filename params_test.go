@@ -0,0 +1,68 @@
+package autowired_test
+
+import (
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+)
+
+// StorageParams is a parameter group shared by several parameter objects
+type StorageParams struct {
+	Primary *TestService `autowire:"primary"`
+	Replica *TestService `autowire:"replica"`
+}
+
+type ReportParams struct {
+	autowired.In
+	StorageParams
+
+	Request *RequestContext
+	Skipped *TestService `autowire:"-"`
+}
+
+type ReportService struct {
+	Params ReportParams
+}
+
+// Test a parameter object resolves its own fields and those of the groups it embeds
+func TestParamObject(t *testing.T) {
+	container := autowired.NewContainer()
+
+	for _, name := range []string{"primary", "replica"} {
+		value := name
+		_ = autowired.Register[TestService](container, func() *TestService {
+			return &TestService{Value: value}
+		}, name)
+	}
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{ID: 7}
+	}, autowired.Prototype)
+	_ = autowired.Register[ReportService](container, func(p ReportParams) *ReportService {
+		return &ReportService{Params: p}
+	})
+
+	if err := container.Validate(); err != nil {
+		t.Fatalf("Failed to validate parameter object: %v", err)
+	}
+
+	service, err := autowired.Resolve[*ReportService](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve ReportService: %v", err)
+	}
+
+	p := service.Params
+	if p.Primary == nil || p.Primary.Value != "primary" || p.Replica == nil || p.Replica.Value != "replica" {
+		t.Errorf("Expected the embedded group to be injected, got %+v", p.StorageParams)
+	}
+	if p.Request == nil || p.Request.ID != 7 {
+		t.Errorf("Expected the direct field to be injected, got %v", p.Request)
+	}
+	if p.Skipped != nil {
+		t.Error("Fields tagged '-' should be left alone")
+	}
+
+	roots := container.Roots()
+	if len(roots) != 1 || roots[0].Type != reflect.TypeOf(&ReportService{}) {
+		t.Errorf("Expected the graph to count every field as a dependency, got roots %v", roots)
+	}
+}
@@ -0,0 +1,23 @@
+package autowired
+
+import "runtime"
+
+// RegisterForPlatform registers T only when the program runs on goos and goarch, so implementations for several
+// platforms can be registered side by side and only the matching one is active. An empty goos or goarch matches
+// any platform. The constructor is checked on every platform, so a mistake does not go unnoticed until the
+// program runs where it matches.
+func RegisterForPlatform[T any](c *Container, goos, goarch string, constructor interface{}, options ...interface{}) error {
+	if onPlatform(goos, goarch) {
+		return Register[T](c, constructor, options...)
+	}
+
+	if err := checkTyped[T](constructor); err != nil {
+		return err
+	}
+	return checkConstructor(constructor, options...)
+}
+
+// onPlatform reports whether goos and goarch match the running platform
+func onPlatform(goos, goarch string) bool {
+	return (goos == "" || goos == runtime.GOOS) && (goarch == "" || goarch == runtime.GOARCH)
+}
@@ -0,0 +1,65 @@
+package autowired_test
+
+import (
+	"errors"
+	"me.sithiramunasinghe/go-autowired"
+	"runtime"
+	"testing"
+)
+
+// Test only the registration for the running platform is active
+func TestRegisterForPlatform(t *testing.T) {
+	container := autowired.NewContainer()
+
+	other := "plan9"
+	if runtime.GOOS == other {
+		other = "linux"
+	}
+
+	err := autowired.RegisterForPlatform[TestService](container, runtime.GOOS, runtime.GOARCH, func() *TestService {
+		return &TestService{Value: "native"}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register native TestService: %v", err)
+	}
+	err = autowired.RegisterForPlatform[TestService](container, other, "", func() *TestService {
+		return &TestService{Value: other}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register %s TestService: %v", other, err)
+	}
+
+	service, err := autowired.Resolve[*TestService](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve TestService: %v", err)
+	}
+	if service.Value != "native" {
+		t.Errorf("Expected the native implementation, got %q", service.Value)
+	}
+}
+
+// Test a constructor for another platform is still checked
+func TestRegisterForPlatformChecksConstructor(t *testing.T) {
+	container := autowired.NewContainer()
+
+	other := "plan9"
+	if runtime.GOOS == other {
+		other = "linux"
+	}
+
+	err := autowired.RegisterForPlatform[TestService](container, other, "", func() *RequestContext {
+		return &RequestContext{}
+	})
+	if !errors.Is(err, &autowired.ErrInvalidConstructor{}) {
+		t.Errorf("Expected ErrInvalidConstructor for the wrong result type, got %v", err)
+	}
+	err = autowired.RegisterForPlatform[TestService](container, other, "", func() (*TestService, string) {
+		return &TestService{}, ""
+	})
+	if !errors.Is(err, &autowired.ErrInvalidConstructor{}) {
+		t.Errorf("Expected ErrInvalidConstructor for a non-error second result, got %v", err)
+	}
+	if nodes := container.RegisteredTypes(); len(nodes) != 0 {
+		t.Errorf("Expected nothing to be registered, got %v", nodes)
+	}
+}
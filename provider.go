@@ -0,0 +1,35 @@
+package autowired
+
+import (
+	"context"
+	"reflect"
+)
+
+// Provider resolves a fresh T from the container on every call. A constructor parameter of type Provider[T]
+// receives one, so a singleton can create prototypes on demand instead of holding a single instance.
+type Provider[T any] func() (T, error)
+
+// providerBinder is implemented by *Provider[T], so constructor parameters of any Provider type can be filled
+type providerBinder interface {
+	provide(ctx context.Context, c *Container)
+}
+
+var providerBinderType = reflect.TypeOf((*providerBinder)(nil)).Elem()
+
+func (p *Provider[T]) provide(ctx context.Context, c *Container) {
+	ctx = lazyContext{ctx}
+	*p = func() (T, error) {
+		return ResolveContext[T](ctx, c)
+	}
+}
+
+func isProvider(typ reflect.Type) bool {
+	return reflect.PtrTo(typ).Implements(providerBinderType)
+}
+
+// newProvider builds the Provider value of type typ for a constructor parameter
+func (c *Container) newProvider(ctx context.Context, typ reflect.Type) reflect.Value {
+	provider := reflect.New(typ)
+	provider.Interface().(providerBinder).provide(ctx, c)
+	return provider.Elem()
+}
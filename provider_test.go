@@ -0,0 +1,90 @@
+package autowired_test
+
+import (
+	"me.sithiramunasinghe/go-autowired"
+	"testing"
+)
+
+type Job struct {
+	ID int
+}
+
+type JobRunner struct {
+	NewJob autowired.Provider[*Job]
+}
+
+// Test a singleton creates distinct prototypes through an injected Provider
+func TestProvider(t *testing.T) {
+	container := autowired.NewContainer()
+
+	next := 0
+	_ = autowired.Register[Job](container, func() *Job {
+		next++
+		return &Job{ID: next}
+	}, autowired.Prototype)
+	_ = autowired.Register[JobRunner](container, func(newJob autowired.Provider[*Job]) *JobRunner {
+		return &JobRunner{NewJob: newJob}
+	})
+
+	if err := container.Validate(); err != nil {
+		t.Fatalf("Failed to validate container: %v", err)
+	}
+
+	runner, err := autowired.Resolve[*JobRunner](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve JobRunner: %v", err)
+	}
+
+	first, err := runner.NewJob()
+	if err != nil {
+		t.Fatalf("Failed to provide first job: %v", err)
+	}
+	second, err := runner.NewJob()
+	if err != nil {
+		t.Fatalf("Failed to provide second job: %v", err)
+	}
+
+	if first == second || first.ID == second.ID {
+		t.Errorf("Expected two distinct jobs, got %+v and %+v", first, second)
+	}
+}
+
+type ProviderApp struct {
+	Scheduler *ProviderScheduler
+}
+
+type ProviderScheduler struct {
+	NewJob autowired.Provider[*ProviderJob]
+}
+
+type ProviderJob struct {
+	App *ProviderApp
+}
+
+// Test a Provider breaks a cycle once the resolution that injected it has finished
+func TestProviderBreaksCycle(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[ProviderApp](container, func(s *ProviderScheduler) *ProviderApp {
+		return &ProviderApp{Scheduler: s}
+	})
+	_ = autowired.Register[ProviderScheduler](container, func(newJob autowired.Provider[*ProviderJob]) *ProviderScheduler {
+		return &ProviderScheduler{NewJob: newJob}
+	})
+	_ = autowired.Register[ProviderJob](container, func(app *ProviderApp) *ProviderJob {
+		return &ProviderJob{App: app}
+	}, autowired.Prototype)
+
+	app, err := autowired.Resolve[*ProviderApp](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve ProviderApp: %v", err)
+	}
+
+	job, err := app.Scheduler.NewJob()
+	if err != nil {
+		t.Fatalf("Failed to provide a job: %v", err)
+	}
+	if job.App != app {
+		t.Error("Expected the job to receive the resolved app")
+	}
+}
@@ -0,0 +1,54 @@
+package autowired
+
+import (
+	"context"
+	"reflect"
+)
+
+// Registry gives lazy access to every named registration of T. A constructor parameter of type Registry[T]
+// receives one; each registration is resolved on the first Get of its name.
+type Registry[T any] struct {
+	container *Container
+	ctx       context.Context
+	names     []string
+}
+
+// registryBinder is implemented by *Registry[T], so constructor parameters of any Registry type can be filled
+type registryBinder interface {
+	bind(ctx context.Context, c *Container)
+}
+
+var registryBinderType = reflect.TypeOf((*registryBinder)(nil)).Elem()
+
+func (r *Registry[T]) bind(ctx context.Context, c *Container) {
+	r.container = c
+	r.ctx = lazyContext{ctx}
+	r.names = c.namesFor(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Get resolves the registration of T named name
+func (r Registry[T]) Get(name string) (T, bool) {
+	if r.container == nil {
+		var zero T
+		return zero, false
+	}
+
+	instance, err := ResolveContext[T](r.ctx, r.container, name)
+	return instance, err == nil
+}
+
+// Names returns the names T was registered under when the registry was injected, in sorted order
+func (r Registry[T]) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+func isRegistry(typ reflect.Type) bool {
+	return reflect.PtrTo(typ).Implements(registryBinderType)
+}
+
+// newRegistry builds the Registry value of type typ for a constructor parameter
+func (c *Container) newRegistry(ctx context.Context, typ reflect.Type) reflect.Value {
+	registry := reflect.New(typ)
+	registry.Interface().(registryBinder).bind(ctx, c)
+	return registry.Elem()
+}
@@ -0,0 +1,93 @@
+package autowired_test
+
+import (
+	"context"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+)
+
+type Router struct {
+	Handlers autowired.Registry[Greeter]
+}
+
+// Test a Registry parameter looks up named registrations lazily
+func TestRegistry(t *testing.T) {
+	container := autowired.NewContainer()
+
+	built := make(map[string]int)
+	for _, name := range []string{"english", "french"} {
+		name := name
+		err := autowired.RegisterFuncPrototype[Greeter](container, func(ctx context.Context, c *autowired.Container) (Greeter, error) {
+			built[name]++
+			if name == "french" {
+				return frenchGreeter{}, nil
+			}
+			return &englishGreeter{Service: &TestService{Value: name}}, nil
+		}, name)
+		if err != nil {
+			t.Fatalf("Failed to register %s greeter: %v", name, err)
+		}
+	}
+	_ = autowired.Register[Router](container, func(handlers autowired.Registry[Greeter]) *Router {
+		return &Router{Handlers: handlers}
+	})
+
+	router, err := autowired.Resolve[*Router](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Router: %v", err)
+	}
+
+	if names := router.Handlers.Names(); !reflect.DeepEqual(names, []string{"english", "french"}) {
+		t.Errorf("Expected names [english french], got %v", names)
+	}
+	if len(built) != 0 {
+		t.Errorf("Expected no greeter to be built before lookup, got %v", built)
+	}
+
+	greeter, ok := router.Handlers.Get("french")
+	if !ok || greeter.Greet() != "bonjour" {
+		t.Errorf("Expected the french greeter, got %v", greeter)
+	}
+	if built["french"] != 1 || built["english"] != 0 {
+		t.Errorf("Expected only the french greeter to be built, got %v", built)
+	}
+
+	if _, ok := router.Handlers.Get("german"); ok {
+		t.Error("Expected no greeter named german")
+	}
+
+	if err := container.Validate(); err != nil {
+		t.Errorf("Expected Registry parameters to validate, got %v", err)
+	}
+}
+
+type Dispatcher struct {
+	Handlers autowired.Registry[*DispatchHandler]
+}
+
+type DispatchHandler struct {
+	Dispatcher *Dispatcher
+}
+
+// Test a Registry looks up registrations depending back on its holder once the holder is resolved
+func TestRegistryBreaksCycle(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[Dispatcher](container, func(handlers autowired.Registry[*DispatchHandler]) *Dispatcher {
+		return &Dispatcher{Handlers: handlers}
+	})
+	_ = autowired.Register[DispatchHandler](container, func(d *Dispatcher) *DispatchHandler {
+		return &DispatchHandler{Dispatcher: d}
+	}, "orders")
+
+	dispatcher, err := autowired.Resolve[*Dispatcher](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Dispatcher: %v", err)
+	}
+
+	handler, ok := dispatcher.Handlers.Get("orders")
+	if !ok || handler.Dispatcher != dispatcher {
+		t.Errorf("Expected the orders handler wired to the dispatcher, got %v", handler)
+	}
+}
@@ -0,0 +1,328 @@
+package autowired
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// scopeKey identifies the request scope of one container, so that containers
+// sharing a context never see each other's scopes
+type scopeKey struct {
+	container *Container
+}
+
+// RequestScope holds the request-scoped instances created for one request
+type RequestScope struct {
+	mu        sync.Mutex
+	instances map[*dependencyInfo]interface{}
+	owned     []scopedInstance
+	shadows   map[reflect.Type]interface{}
+	destroyed bool
+	cancel    context.CancelFunc
+}
+
+// scopedInstance is an instance the scope tears down when it is destroyed
+type scopedInstance struct {
+	info     *dependencyInfo
+	instance interface{}
+}
+
+// ScopeStats reports request scope activity for diagnosing scope leaks
+type ScopeStats struct {
+	Created   int64
+	Destroyed int64
+	Active    int64
+	Instances int64
+}
+
+// CreateScope returns a context carrying a new request scope.
+// Request-scoped dependencies resolved with this context are cached in the scope
+// until DestroyScope is called. The returned context is cancelled when the scope is destroyed,
+// so goroutines started for the request get a signal to stop.
+func (c *Container) CreateScope(ctx context.Context) context.Context {
+	atomic.AddInt64(&c.scopesCreated, 1)
+	ctx, cancel := context.WithCancel(ctx)
+	return context.WithValue(ctx, scopeKey{container: c}, &RequestScope{
+		instances: make(map[*dependencyInfo]interface{}),
+		cancel:    cancel,
+	})
+}
+
+// ScopeHandle is a request scope bound to its container, so request handlers can resolve from it with
+// ScopeResolve instead of threading the scope's context through
+type ScopeHandle struct {
+	container *Container
+	ctx       context.Context
+}
+
+// BeginScope is like CreateScope but returns a handle on the new scope
+func (c *Container) BeginScope(ctx context.Context) *ScopeHandle {
+	return &ScopeHandle{container: c, ctx: c.CreateScope(ctx)}
+}
+
+// Context returns the context carrying the scope, which is cancelled when the scope ends
+func (h *ScopeHandle) Context() context.Context {
+	return h.ctx
+}
+
+// Resolve resolves a dependency within the scope
+func (h *ScopeHandle) Resolve(typ reflect.Type, options ...interface{}) (interface{}, error) {
+	return h.container.ResolveContext(h.ctx, typ, options...)
+}
+
+// End destroys the scope, for deferring right after BeginScope
+func (h *ScopeHandle) End() error {
+	return h.container.DestroyScope(h.ctx)
+}
+
+// ScopeResolve resolves T within the scope of h
+func ScopeResolve[T any](h *ScopeHandle, options ...interface{}) (T, error) {
+	return ResolveContext[T](h.ctx, h.container, options...)
+}
+
+// DestroyScope runs the destroy hooks of the instances held by the scope in ctx and releases them.
+// The scope owns the lifecycle of its instances: each one it constructed is destroyed exactly once,
+// in reverse order of construction, whether or not it has an OnStart hook. Registrations never
+// resolved in the scope are not touched. Resolving from the scope afterwards fails with ErrOutsideScope.
+// The scope's context is cancelled before the destroy hooks run.
+func (c *Container) DestroyScope(ctx context.Context) error {
+	scope := c.getScope(ctx)
+	if scope == nil {
+		return nil
+	}
+
+	scope.mu.Lock()
+	if scope.destroyed {
+		scope.mu.Unlock()
+		return nil
+	}
+	scope.destroyed = true
+	owned := scope.owned
+	scope.instances = nil
+	scope.owned = nil
+	scope.mu.Unlock()
+
+	if scope.cancel != nil {
+		scope.cancel()
+	}
+	atomic.AddInt64(&c.scopesDestroyed, 1)
+	atomic.AddInt64(&c.scopedInstances, -int64(len(owned)))
+
+	var firstErr error
+	for i := len(owned) - 1; i >= 0; i-- {
+		if onDestroy := owned[i].info.onDestroy(); onDestroy != nil {
+			if err := onDestroy(owned[i].instance); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// ShadowSingleton makes singletons of typ resolve to instance within the scope in ctx, leaving the
+// global singleton for every other caller. Singletons built during the scope are cached globally, so
+// their own dependencies are never shadowed. The scope does not destroy the shadow instance.
+func (c *Container) ShadowSingleton(ctx context.Context, typ reflect.Type, instance interface{}) error {
+	c.mu.RLock()
+	info, err := c.getDependencyInfo(typ, "")
+	c.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+	if info.scope != Singleton {
+		return fmt.Errorf("cannot shadow %v: only singletons can be shadowed", info.node())
+	}
+	if instance == nil || !reflect.TypeOf(instance).AssignableTo(typ) {
+		return fmt.Errorf("cannot shadow %v with %T", info.node(), instance)
+	}
+
+	scope := c.getScope(ctx)
+	if scope == nil {
+		return &ErrOutsideScope{Type: info.typ, Name: info.name}
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	if scope.shadows == nil {
+		scope.shadows = make(map[reflect.Type]interface{})
+	}
+	scope.shadows[typ] = instance
+	return nil
+}
+
+// shadow returns the instance shadowing singletons of typ in the scope of ctx, if any
+func (c *Container) shadow(ctx context.Context, typ reflect.Type) (interface{}, bool) {
+	scope := c.getScope(ctx)
+	if scope == nil {
+		return nil, false
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	instance, ok := scope.shadows[typ]
+	return instance, ok
+}
+
+// EvictScoped destroys the instance of typ under name held by the scope in ctx and removes it, so the
+// next resolution in the scope builds a new one. The scope's other instances are kept.
+func (c *Container) EvictScoped(ctx context.Context, typ reflect.Type, name string) error {
+	c.mu.RLock()
+	info, err := c.getDependencyInfo(typ, name)
+	c.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	scope := c.getScope(ctx)
+	if scope == nil {
+		return &ErrOutsideScope{Type: info.typ, Name: info.name}
+	}
+
+	scope.mu.Lock()
+	instance, ok := scope.instances[info]
+	if ok {
+		delete(scope.instances, info)
+		// A scope owns one instance per request-scoped registration, and instances need not be comparable
+		for i, owned := range scope.owned {
+			if owned.info == info {
+				scope.owned = append(scope.owned[:i], scope.owned[i+1:]...)
+				break
+			}
+		}
+	}
+	scope.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	atomic.AddInt64(&c.scopedInstances, -1)
+	if onDestroy := info.onDestroy(); onDestroy != nil {
+		return onDestroy(instance)
+	}
+	return nil
+}
+
+// ScopeStats returns counters for the request scopes created by the container
+func (c *Container) ScopeStats() ScopeStats {
+	created := atomic.LoadInt64(&c.scopesCreated)
+	destroyed := atomic.LoadInt64(&c.scopesDestroyed)
+	return ScopeStats{
+		Created:   created,
+		Destroyed: destroyed,
+		Active:    created - destroyed,
+		Instances: atomic.LoadInt64(&c.scopedInstances),
+	}
+}
+
+// CurrentScope returns the request scope carried by ctx
+func (c *Container) CurrentScope(ctx context.Context) (*RequestScope, bool) {
+	scope := c.getScope(ctx)
+	return scope, scope != nil
+}
+
+// PropagateScope returns to carrying the request scope of from, so a goroutine started with a fresh context can
+// resolve the request-scoped instances of the request that spawned it. to is returned unchanged when from has no
+// scope. The scope still ends when its request destroys it: a goroutine that outlives it gets ErrOutsideScope
+// from then on, and instances it already holds have been destroyed underneath it.
+func (c *Container) PropagateScope(from, to context.Context) context.Context {
+	scope := c.getScope(from)
+	if scope == nil {
+		return to
+	}
+	return context.WithValue(to, scopeKey{container: c}, scope)
+}
+
+// InstanceCount returns the number of instances the scope currently owns
+func (s *RequestScope) InstanceCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.owned)
+}
+
+// Destroyed reports whether the scope has been destroyed
+func (s *RequestScope) Destroyed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.destroyed
+}
+
+func (c *Container) getScope(ctx context.Context) *RequestScope {
+	scope, _ := ctx.Value(scopeKey{container: c}).(*RequestScope)
+	return scope
+}
+
+func (c *Container) resolveScoped(ctx context.Context, scope *RequestScope, info *dependencyInfo) (interface{}, error) {
+	scope.mu.Lock()
+	instance, ok := scope.instances[info]
+	destroyed := scope.destroyed
+	scope.mu.Unlock()
+	if ok {
+		return instance, nil
+	}
+	if destroyed {
+		return nil, &ErrOutsideScope{Type: info.typ, Name: info.name}
+	}
+
+	instance, err := c.construct(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	scope.mu.Lock()
+	existing, raced := scope.instances[info]
+	destroyed = scope.destroyed
+	if !raced && !destroyed {
+		scope.instances[info] = instance
+		scope.owned = append(scope.owned, scopedInstance{info: info, instance: instance})
+		atomic.AddInt64(&c.scopedInstances, 1)
+	}
+	scope.mu.Unlock()
+
+	switch {
+	case raced:
+		// Another goroutine built the scope's instance first; nothing else will stop this one
+		discard(info, instance)
+		return existing, nil
+	case destroyed:
+		// The scope was destroyed while this instance was built; nothing else will stop it
+		discard(info, instance)
+		return nil, &ErrOutsideScope{Type: info.typ, Name: info.name}
+	}
+	return instance, nil
+}
+
+// track hands a prototype instance to the scope so it is destroyed with it. A scope destroyed meanwhile can
+// no longer stop the instance, so it is destroyed right away and ErrOutsideScope returned.
+func (c *Container) track(scope *RequestScope, info *dependencyInfo, instance interface{}) error {
+	scope.mu.Lock()
+	destroyed := scope.destroyed
+	if !destroyed {
+		scope.owned = append(scope.owned, scopedInstance{info: info, instance: instance})
+		atomic.AddInt64(&c.scopedInstances, 1)
+	}
+	scope.mu.Unlock()
+
+	if destroyed {
+		discard(info, instance)
+		return &ErrOutsideScope{Type: info.typ, Name: info.name}
+	}
+	return nil
+}
+
+// discard runs the destroy hook of an instance no scope owns
+func discard(info *dependencyInfo, instance interface{}) {
+	if onDestroy := info.onDestroy(); onDestroy != nil {
+		_ = onDestroy(instance)
+	}
+}
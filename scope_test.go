@@ -0,0 +1,649 @@
+package autowired_test
+
+import (
+	"context"
+	"errors"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type RequestContext struct {
+	ID int
+}
+
+// Test request-scoped instances are cached per scope
+func TestRequestScope(t *testing.T) {
+	container := autowired.NewContainer()
+
+	created := 0
+	err := autowired.Register[RequestContext](container, func() *RequestContext {
+		created++
+		return &RequestContext{ID: created}
+	}, autowired.Request)
+	if err != nil {
+		t.Fatalf("Failed to register RequestContext: %v", err)
+	}
+
+	ctx := container.CreateScope(context.Background())
+	first, err := autowired.ResolveContext[*RequestContext](ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to resolve RequestContext: %v", err)
+	}
+	second, _ := autowired.ResolveContext[*RequestContext](ctx, container)
+
+	if first != second {
+		t.Error("Request-scoped instances should be the same within a scope")
+	}
+
+	other := container.CreateScope(context.Background())
+	third, _ := autowired.ResolveContext[*RequestContext](other, container)
+
+	if third == first {
+		t.Error("Request-scoped instances should differ across scopes")
+	}
+}
+
+// Test scope statistics return to zero active scopes once destroyed
+func TestScopeStats(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Request)
+	if err != nil {
+		t.Fatalf("Failed to register RequestContext: %v", err)
+	}
+
+	var scopes []context.Context
+	for i := 0; i < 3; i++ {
+		ctx := container.CreateScope(context.Background())
+		if _, err := autowired.ResolveContext[*RequestContext](ctx, container); err != nil {
+			t.Fatalf("Failed to resolve RequestContext: %v", err)
+		}
+		scopes = append(scopes, ctx)
+	}
+
+	stats := container.ScopeStats()
+	if stats.Created != 3 || stats.Active != 3 || stats.Instances != 3 {
+		t.Errorf("Expected 3 created, active and held instances, got %+v", stats)
+	}
+
+	for _, ctx := range scopes {
+		if err := container.DestroyScope(ctx); err != nil {
+			t.Fatalf("Failed to destroy scope: %v", err)
+		}
+	}
+	// Destroying twice must not be counted again
+	_ = container.DestroyScope(scopes[0])
+
+	stats = container.ScopeStats()
+	if stats.Destroyed != 3 || stats.Active != 0 || stats.Instances != 0 {
+		t.Errorf("Expected 3 destroyed and nothing active, got %+v", stats)
+	}
+}
+
+// Test prototypes resolved within a scope are cleaned up with it
+func TestTrackPrototypes(t *testing.T) {
+	container := autowired.NewContainer()
+	container.TrackPrototypes = true
+
+	cleaned := 0
+	err := autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Prototype, autowired.LifecycleHooks[*RequestContext]{
+		OnDestroy: func(r *RequestContext) error {
+			cleaned++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register RequestContext: %v", err)
+	}
+
+	ctx := container.CreateScope(context.Background())
+	_, _ = autowired.ResolveContext[*RequestContext](ctx, container)
+	_, _ = autowired.ResolveContext[*RequestContext](ctx, container)
+
+	if cleaned != 0 {
+		t.Error("Prototype cleanup should not run before the scope is destroyed")
+	}
+
+	if err := container.DestroyScope(ctx); err != nil {
+		t.Fatalf("Failed to destroy scope: %v", err)
+	}
+	if cleaned != 2 {
+		t.Errorf("Expected 2 prototype cleanups, got %d", cleaned)
+	}
+
+	// Outside a scope the caller owns the cleanup
+	_, cleanup, err := container.ResolveTracked(context.Background(), reflect.TypeOf(&RequestContext{}))
+	if err != nil {
+		t.Fatalf("Failed to resolve tracked RequestContext: %v", err)
+	}
+	_ = cleanup()
+	_ = cleanup()
+
+	if cleaned != 3 {
+		t.Errorf("Expected tracked cleanup to run once, got %d cleanups", cleaned)
+	}
+
+	// A destroyed scope cannot stop the prototype any more, so it is cleaned up right away
+	if _, err := autowired.ResolveContext[*RequestContext](ctx, container); !errors.Is(err, &autowired.ErrOutsideScope{}) {
+		t.Errorf("Expected ErrOutsideScope from a destroyed scope, got %v", err)
+	}
+	if cleaned != 4 {
+		t.Errorf("Expected the prototype built for a destroyed scope to be cleaned up, got %d cleanups", cleaned)
+	}
+}
+
+// Test the instance losing a race to fill a scope is destroyed
+func TestScopedRaceDestroysDuplicate(t *testing.T) {
+	container := autowired.NewContainer()
+
+	var entered sync.WaitGroup
+	entered.Add(2)
+	var destroyed int32
+	err := autowired.Register[RequestContext](container, func() *RequestContext {
+		// Both resolutions construct before either stores its instance
+		entered.Done()
+		entered.Wait()
+		return &RequestContext{}
+	}, autowired.Request, autowired.LifecycleHooks[*RequestContext]{
+		OnDestroy: func(*RequestContext) error {
+			atomic.AddInt32(&destroyed, 1)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register RequestContext: %v", err)
+	}
+
+	ctx := container.CreateScope(context.Background())
+	results := make(chan *RequestContext, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			instance, _ := autowired.ResolveContext[*RequestContext](ctx, container)
+			results <- instance
+		}()
+	}
+	first, second := <-results, <-results
+
+	if first == nil || first != second {
+		t.Fatalf("Expected both resolutions to get the scope's instance, got %p and %p", first, second)
+	}
+	if n := atomic.LoadInt32(&destroyed); n != 1 {
+		t.Errorf("Expected the losing duplicate to be destroyed, got %d destroyed", n)
+	}
+
+	if err := container.DestroyScope(ctx); err != nil {
+		t.Fatalf("Failed to destroy scope: %v", err)
+	}
+	if n := atomic.LoadInt32(&destroyed); n != 2 {
+		t.Errorf("Expected the scope's instance destroyed with it, got %d destroyed", n)
+	}
+}
+
+// Test the current scope is exposed only inside a created scope
+func TestCurrentScope(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Request)
+
+	if _, ok := container.CurrentScope(context.Background()); ok {
+		t.Error("Expected no scope outside CreateScope")
+	}
+
+	ctx := container.CreateScope(context.Background())
+	scope, ok := container.CurrentScope(ctx)
+	if !ok {
+		t.Fatal("Expected a scope inside CreateScope")
+	}
+
+	_, _ = autowired.ResolveContext[*RequestContext](ctx, container)
+	if scope.InstanceCount() != 1 {
+		t.Errorf("Expected 1 scoped instance, got %d", scope.InstanceCount())
+	}
+
+	_ = container.DestroyScope(ctx)
+	if !scope.Destroyed() || scope.InstanceCount() != 0 {
+		t.Error("Expected destroyed scope to hold no instances")
+	}
+}
+
+// Test containers sharing a context keep their scopes apart
+func TestScopesPerContainer(t *testing.T) {
+	first := autowired.NewContainer()
+	second := autowired.NewContainer()
+
+	for _, container := range []*autowired.Container{first, second} {
+		_ = autowired.Register[RequestContext](container, func() *RequestContext {
+			return &RequestContext{}
+		}, autowired.Request)
+	}
+
+	ctx := first.CreateScope(context.Background())
+	ctx = second.CreateScope(ctx)
+
+	fromFirst, _ := autowired.ResolveContext[*RequestContext](ctx, first)
+	fromSecond, _ := autowired.ResolveContext[*RequestContext](ctx, second)
+
+	if fromFirst == fromSecond {
+		t.Error("Containers should not share scoped instances")
+	}
+
+	firstScope, _ := first.CurrentScope(ctx)
+	secondScope, _ := second.CurrentScope(ctx)
+	if firstScope == secondScope || firstScope.InstanceCount() != 1 || secondScope.InstanceCount() != 1 {
+		t.Error("Each container should own its own scope")
+	}
+
+	_ = second.DestroyScope(ctx)
+	if firstScope.Destroyed() {
+		t.Error("Destroying one container's scope should not destroy the other's")
+	}
+}
+
+// Test context-free resolution of a request-scoped dependency fails clearly
+func TestResolveRequestScopedWithoutContext(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Request)
+
+	_, err := autowired.Resolve[*RequestContext](container)
+	if !errors.Is(err, &autowired.ErrOutsideScope{}) {
+		t.Errorf("Expected ErrOutsideScope, got %v", err)
+	}
+
+	ctx := container.CreateScope(context.Background())
+	if _, err := autowired.ResolveContext[*RequestContext](ctx, container); err != nil {
+		t.Errorf("Expected scoped resolution to succeed, got %v", err)
+	}
+}
+
+// Test destroying a scope stops every scoped instance exactly once, whatever hooks ran before
+func TestDestroyScopeLifecycleMatrix(t *testing.T) {
+	container := autowired.NewContainer()
+
+	destroyed := make(map[string]int)
+	hooks := func(name string, withStart bool) autowired.LifecycleHooks[*RequestContext] {
+		hooks := autowired.LifecycleHooks[*RequestContext]{
+			OnInit: func(*RequestContext) error { return nil },
+			OnDestroy: func(*RequestContext) error {
+				destroyed[name]++
+				return nil
+			},
+		}
+		if withStart {
+			hooks.OnStart = func(*RequestContext) error { return nil }
+		}
+		return hooks
+	}
+
+	for _, entry := range []struct {
+		name      string
+		withStart bool
+	}{{"initOnly", false}, {"started", true}, {"neverResolved", true}} {
+		err := autowired.Register[RequestContext](container, func() *RequestContext {
+			return &RequestContext{}
+		}, entry.name, autowired.Request, hooks(entry.name, entry.withStart))
+		if err != nil {
+			t.Fatalf("Failed to register %s: %v", entry.name, err)
+		}
+	}
+
+	ctx := container.CreateScope(context.Background())
+	for _, name := range []string{"initOnly", "started", "initOnly"} {
+		if _, err := autowired.ResolveContext[*RequestContext](ctx, container, name); err != nil {
+			t.Fatalf("Failed to resolve %s: %v", name, err)
+		}
+	}
+
+	if err := container.DestroyScope(ctx); err != nil {
+		t.Fatalf("Failed to destroy scope: %v", err)
+	}
+	if err := container.DestroyScope(ctx); err != nil {
+		t.Fatalf("Failed to destroy scope twice: %v", err)
+	}
+
+	if destroyed["initOnly"] != 1 || destroyed["started"] != 1 || destroyed["neverResolved"] != 0 {
+		t.Errorf("Expected init-only and started instances destroyed once and the unresolved one untouched, got %v", destroyed)
+	}
+	if scope, _ := container.CurrentScope(ctx); scope.InstanceCount() != 0 {
+		t.Errorf("Expected the destroyed scope to own no instances, got %d", scope.InstanceCount())
+	}
+
+	_, err := autowired.ResolveContext[*RequestContext](ctx, container, "neverResolved")
+	if !errors.Is(err, &autowired.ErrOutsideScope{}) {
+		t.Errorf("Expected ErrOutsideScope from a destroyed scope, got %v", err)
+	}
+}
+
+// Test a request-scoped factory runs once per scope
+func TestScopedFactoryCaching(t *testing.T) {
+	container := autowired.NewContainer()
+
+	calls := 0
+	err := autowired.RegisterFuncRequest[*RequestContext](container, func(ctx context.Context, c *autowired.Container) (*RequestContext, error) {
+		calls++
+		return &RequestContext{ID: calls}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register request factory: %v", err)
+	}
+
+	first := container.CreateScope(context.Background())
+	a, _ := autowired.ResolveContext[*RequestContext](first, container)
+	b, _ := autowired.ResolveContext[*RequestContext](first, container)
+	if calls != 1 || a != b {
+		t.Errorf("Expected the factory to run once within a scope, ran %d times", calls)
+	}
+
+	second := container.CreateScope(context.Background())
+	c, _ := autowired.ResolveContext[*RequestContext](second, container)
+	if calls != 2 || c == a {
+		t.Errorf("Expected a fresh instance in a new scope, factory ran %d times", calls)
+	}
+}
+
+// Test evicting one scoped instance rebuilds only that instance
+func TestEvictScoped(t *testing.T) {
+	container := autowired.NewContainer()
+
+	built := make(map[string]int)
+	destroyed := make(map[string]int)
+	for _, tenant := range []string{"acme", "globex"} {
+		tenant := tenant
+		err := autowired.Register[RequestContext](container, func() *RequestContext {
+			built[tenant]++
+			return &RequestContext{ID: built[tenant]}
+		}, tenant, autowired.Request, autowired.LifecycleHooks[*RequestContext]{
+			OnDestroy: func(*RequestContext) error {
+				destroyed[tenant]++
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to register %s: %v", tenant, err)
+		}
+	}
+
+	ctx := container.CreateScope(context.Background())
+	acme, _ := autowired.ResolveContext[*RequestContext](ctx, container, "acme")
+	globex, _ := autowired.ResolveContext[*RequestContext](ctx, container, "globex")
+
+	if err := container.EvictScoped(ctx, reflect.TypeOf(&RequestContext{}), "acme"); err != nil {
+		t.Fatalf("Failed to evict acme: %v", err)
+	}
+	if destroyed["acme"] != 1 || destroyed["globex"] != 0 {
+		t.Errorf("Expected only acme to be destroyed, got %v", destroyed)
+	}
+
+	rebuilt, _ := autowired.ResolveContext[*RequestContext](ctx, container, "acme")
+	kept, _ := autowired.ResolveContext[*RequestContext](ctx, container, "globex")
+	if rebuilt == acme || rebuilt.ID != 2 {
+		t.Errorf("Expected acme to be rebuilt after eviction, got %+v", rebuilt)
+	}
+	if kept != globex {
+		t.Error("Expected globex to persist in the scope")
+	}
+
+	if err := container.DestroyScope(ctx); err != nil {
+		t.Fatalf("Failed to destroy scope: %v", err)
+	}
+	if destroyed["acme"] != 2 || destroyed["globex"] != 1 {
+		t.Errorf("Expected each live instance destroyed once with the scope, got %v", destroyed)
+	}
+
+	if err := container.EvictScoped(context.Background(), reflect.TypeOf(&RequestContext{}), "acme"); !errors.Is(err, &autowired.ErrOutsideScope{}) {
+		t.Errorf("Expected ErrOutsideScope without a scope, got %v", err)
+	}
+}
+
+type Tenant struct {
+	Roles []string
+}
+
+// Test evicting a scoped value that cannot be compared with == destroys it
+func TestEvictScopedUncomparable(t *testing.T) {
+	container := autowired.NewContainer()
+
+	destroyed := 0
+	err := autowired.RegisterRequestFactoryWithHooks[Tenant](container, func(ctx context.Context, c *autowired.Container) (Tenant, error) {
+		return Tenant{Roles: []string{"admin"}}, nil
+	}, autowired.LifecycleHooks[Tenant]{OnDestroy: func(Tenant) error {
+		destroyed++
+		return nil
+	}})
+	if err != nil {
+		t.Fatalf("Failed to register Tenant: %v", err)
+	}
+
+	ctx := container.CreateScope(context.Background())
+	if _, err := autowired.ResolveContext[Tenant](ctx, container); err != nil {
+		t.Fatalf("Failed to resolve Tenant: %v", err)
+	}
+
+	if err := container.EvictScoped(ctx, reflect.TypeOf(Tenant{}), "tenant"); err != nil {
+		t.Fatalf("Failed to evict Tenant: %v", err)
+	}
+	if err := container.DestroyScope(ctx); err != nil {
+		t.Fatalf("Failed to destroy scope: %v", err)
+	}
+	if destroyed != 1 {
+		t.Errorf("Expected the evicted Tenant to be destroyed once, got %d", destroyed)
+	}
+}
+
+// Test a shadowed singleton is used only inside its scope
+func TestShadowSingleton(t *testing.T) {
+	container := autowired.NewContainer()
+	_ = autowired.Register[TestService](container, NewTestService)
+
+	ctx := container.CreateScope(context.Background())
+	shadow := &TestService{Value: "shadow"}
+	if err := container.ShadowSingleton(ctx, reflect.TypeOf(&TestService{}), shadow); err != nil {
+		t.Fatalf("Failed to shadow TestService: %v", err)
+	}
+
+	scoped, err := autowired.ResolveContext[*TestService](ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to resolve TestService in scope: %v", err)
+	}
+	if scoped != shadow {
+		t.Errorf("Expected the shadow inside the scope, got %+v", scoped)
+	}
+
+	global, err := autowired.ResolveContext[*TestService](container.CreateScope(context.Background()), container)
+	if err != nil {
+		t.Fatalf("Failed to resolve TestService in another scope: %v", err)
+	}
+	if global.Value != "default" {
+		t.Errorf("Expected the global singleton elsewhere, got %+v", global)
+	}
+
+	if err := container.ShadowSingleton(context.Background(), reflect.TypeOf(&TestService{}), shadow); !errors.Is(err, &autowired.ErrOutsideScope{}) {
+		t.Errorf("Expected ErrOutsideScope without a scope, got %v", err)
+	}
+	if err := container.ShadowSingleton(ctx, reflect.TypeOf(&TestService{}), &RequestContext{}); err == nil {
+		t.Error("Expected error for a shadow of the wrong type, got nil")
+	}
+}
+
+// Test request-scoped resolution without a scope fails when scopes are required
+func TestRequireScopes(t *testing.T) {
+	container := autowired.NewContainer()
+	container.RequireScopes = true
+
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Request)
+
+	_, err := autowired.ResolveContext[*RequestContext](context.Background(), container)
+	if !errors.Is(err, &autowired.ErrOutsideScope{}) {
+		t.Errorf("Expected ErrOutsideScope without a scope, got %v", err)
+	}
+
+	ctx := container.CreateScope(context.Background())
+	if _, err := autowired.ResolveContext[*RequestContext](ctx, container); err != nil {
+		t.Errorf("Expected scoped resolution to succeed, got %v", err)
+	}
+}
+
+// Test a goroutine with a fresh context resolves the instances of the scope propagated to it
+func TestPropagateScope(t *testing.T) {
+	container := autowired.NewContainer()
+	container.RequireScopes = true
+
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Request)
+
+	ctx := container.CreateScope(context.Background())
+	owned, err := autowired.ResolveContext[*RequestContext](ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to resolve RequestContext: %v", err)
+	}
+
+	if same := container.PropagateScope(context.Background(), context.Background()); same != context.Background() {
+		t.Error("Propagating from a context without a scope should return the target unchanged")
+	}
+
+	type result struct {
+		instance *RequestContext
+		err      error
+	}
+	done := make(chan result)
+	background := container.PropagateScope(ctx, context.Background())
+	go func() {
+		instance, err := autowired.ResolveContext[*RequestContext](background, container)
+		done <- result{instance, err}
+	}()
+
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("Failed to resolve RequestContext in the background: %v", r.err)
+	}
+	if r.instance != owned {
+		t.Error("The background goroutine should see the instance of the originating scope")
+	}
+
+	_ = container.DestroyScope(ctx)
+	if _, err := autowired.ResolveContext[*RequestContext](background, container); !errors.Is(err, &autowired.ErrOutsideScope{}) {
+		t.Errorf("Expected ErrOutsideScope once the scope is destroyed, got %v", err)
+	}
+}
+
+// Test registrations without a scope pick up the container's default lifetime
+func TestSetDefaultLifetime(t *testing.T) {
+	container := autowired.NewContainer()
+	container.SetDefaultLifetime(autowired.Request)
+
+	err := autowired.RegisterDefault[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register RequestContext: %v", err)
+	}
+	_ = autowired.RegisterDefault[TestService](container, NewTestService, autowired.Singleton)
+
+	first := container.CreateScope(context.Background())
+	second := container.CreateScope(context.Background())
+
+	a, _ := autowired.ResolveContext[*RequestContext](first, container)
+	b, _ := autowired.ResolveContext[*RequestContext](first, container)
+	c, _ := autowired.ResolveContext[*RequestContext](second, container)
+	if a != b || a == c {
+		t.Error("A default-registered component should be cached per scope")
+	}
+
+	one, _ := autowired.ResolveContext[*TestService](first, container)
+	two, _ := autowired.ResolveContext[*TestService](second, container)
+	if one != two {
+		t.Error("An explicit scope should override the default lifetime")
+	}
+}
+
+// Test the context of a scope is cancelled when the scope is destroyed
+func TestBeginScopeCancellation(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Request)
+
+	scope := container.BeginScope(context.Background())
+	ctx := scope.Context()
+	if _, err := autowired.ResolveContext[*RequestContext](ctx, container); err != nil {
+		t.Fatalf("Failed to resolve RequestContext: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("The scope context should not be cancelled before the scope ends")
+	default:
+	}
+
+	if err := scope.End(); err != nil {
+		t.Fatalf("Failed to end scope: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the scope context to be cancelled once the scope is destroyed")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", ctx.Err())
+	}
+}
+
+// Test a scope handle resolves request-scoped instances of its own scope
+func TestScopeHandle(t *testing.T) {
+	container := autowired.NewContainer()
+	container.RequireScopes = true
+
+	next := 0
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		next++
+		return &RequestContext{ID: next}
+	}, autowired.Request)
+
+	first := container.BeginScope(context.Background())
+	second := container.BeginScope(context.Background())
+
+	a, err := autowired.ScopeResolve[*RequestContext](first)
+	if err != nil {
+		t.Fatalf("Failed to resolve RequestContext: %v", err)
+	}
+	again, err := first.Resolve(reflect.TypeOf(&RequestContext{}))
+	if err != nil {
+		t.Fatalf("Failed to resolve RequestContext: %v", err)
+	}
+	b, err := autowired.ScopeResolve[*RequestContext](second)
+	if err != nil {
+		t.Fatalf("Failed to resolve RequestContext: %v", err)
+	}
+
+	if again != a {
+		t.Error("Expected the same instance within a scope")
+	}
+	if a == b {
+		t.Error("Expected a distinct instance per scope")
+	}
+
+	if err := first.End(); err != nil {
+		t.Fatalf("Failed to end scope: %v", err)
+	}
+	if _, err := autowired.ScopeResolve[*RequestContext](first); !errors.Is(err, &autowired.ErrOutsideScope{}) {
+		t.Errorf("Expected ErrOutsideScope from an ended scope, got %v", err)
+	}
+	_ = second.End()
+}
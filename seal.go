@@ -0,0 +1,58 @@
+package autowired
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+)
+
+// SealedContainer is a read-only view of a container whose registrations can no longer change.
+// Lookups skip the container lock; only the singleton caches are synchronized.
+type SealedContainer struct {
+	container *Container
+}
+
+// Seal freezes the registrations of the container and returns a read-only view of it.
+// Afterwards registering or deprecating fails with ErrSealed, and resolution no longer takes the
+// container lock, whether it goes through the SealedContainer or the container itself.
+func (c *Container) Seal() *SealedContainer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	atomic.StoreInt32(&c.sealed, 1)
+	return &SealedContainer{container: c}
+}
+
+// Container returns the sealed container, for use with the generic resolution helpers
+func (s *SealedContainer) Container() *Container {
+	return s.container
+}
+
+// Resolve resolves a dependency from the sealed container
+func (s *SealedContainer) Resolve(typ reflect.Type, options ...interface{}) (interface{}, error) {
+	return s.container.Resolve(typ, options...)
+}
+
+// ResolveContext resolves a dependency from the sealed container with the given context
+func (s *SealedContainer) ResolveContext(ctx context.Context, typ reflect.Type, options ...interface{}) (interface{}, error) {
+	return s.container.ResolveContext(ctx, typ, options...)
+}
+
+func (c *Container) isSealed() bool {
+	return atomic.LoadInt32(&c.sealed) == 1
+}
+
+// lookup returns the registration of typ under name and its deprecation message.
+// Registrations of a sealed container never change, so they are read without the lock.
+func (c *Container) lookup(typ reflect.Type, name string) (*dependencyInfo, string, error) {
+	if !c.isSealed() {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+	}
+
+	info, err := c.getDependencyInfo(typ, name)
+	if err != nil {
+		return nil, "", err
+	}
+	return info, info.deprecation, nil
+}
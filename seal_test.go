@@ -0,0 +1,58 @@
+package autowired_test
+
+import (
+	"errors"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+)
+
+// Test a sealed container resolves but refuses new registrations
+func TestSeal(t *testing.T) {
+	container := autowired.NewContainer()
+	_ = autowired.Register[TestService](container, NewTestService)
+
+	sealed := container.Seal()
+
+	instance, err := sealed.Resolve(reflect.TypeOf(&TestService{}))
+	if err != nil {
+		t.Fatalf("Failed to resolve from sealed container: %v", err)
+	}
+	service, err := autowired.Resolve[*TestService](sealed.Container())
+	if err != nil || service != instance {
+		t.Errorf("Expected the generic helpers to share the sealed singleton, got %v", err)
+	}
+
+	err = autowired.Register[RequestContext](container, func() *RequestContext { return &RequestContext{} })
+	if !errors.Is(err, &autowired.ErrSealed{}) {
+		t.Errorf("Expected ErrSealed when registering after Seal, got %v", err)
+	}
+	if err := container.Deprecate(reflect.TypeOf(&TestService{}), "", "gone"); !errors.Is(err, &autowired.ErrSealed{}) {
+		t.Errorf("Expected ErrSealed when deprecating after Seal, got %v", err)
+	}
+}
+
+func BenchmarkResolveConcurrent(b *testing.B) {
+	for _, sealed := range []bool{false, true} {
+		name := "unsealed"
+		if sealed {
+			name = "sealed"
+		}
+		b.Run(name, func(b *testing.B) {
+			container := autowired.NewContainer()
+			_ = autowired.Register[TestService](container, NewTestService)
+			if sealed {
+				container.Seal()
+			}
+
+			typ := reflect.TypeOf(&TestService{})
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := container.Resolve(typ); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
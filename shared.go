@@ -0,0 +1,66 @@
+package autowired
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// sharedSlot holds the instance shared by every registration with the same key
+type sharedSlot struct {
+	mu       sync.Mutex
+	instance interface{}
+	built    bool
+}
+
+// RegisterShared registers a singleton of T that shares its instance with every other registration made
+// under key, whatever their types. The first of them to be resolved constructs the instance, which must be
+// assignable to each registered type. Destroy hooks attached to several of them all run on the shared instance.
+func RegisterShared[T any](c *Container, key string, constructor interface{}, options ...interface{}) error {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	constructorType := reflect.TypeOf(constructor)
+	if constructorType == nil || constructorType.Kind() != reflect.Func || constructorType.NumOut() == 0 {
+		return &ErrInvalidConstructor{Reason: "constructor must be a function"}
+	}
+	if !constructorType.Out(0).AssignableTo(typ) {
+		return &ErrInvalidConstructor{Reason: fmt.Sprintf("constructor returns %v, which is not assignable to %v", constructorType.Out(0), typ)}
+	}
+
+	slot := c.sharedSlot(key)
+	factory := func(ctx context.Context, c *Container) (interface{}, error) {
+		slot.mu.Lock()
+		defer slot.mu.Unlock()
+
+		if !slot.built {
+			results, err := c.invoke(ctx, constructor)
+			if err != nil {
+				return nil, err
+			}
+			slot.instance, slot.built = results[0].Interface(), true
+		}
+
+		if slot.instance != nil && !reflect.TypeOf(slot.instance).AssignableTo(typ) {
+			return nil, fmt.Errorf("instance shared under '%s' is %T, which is not assignable to %v", key, slot.instance, typ)
+		}
+		return slot.instance, nil
+	}
+
+	// The constructor stays on the registration to describe its dependencies
+	return c.register(typ, &dependencyInfo{constructor: reflect.ValueOf(constructor), factory: factory}, append(options, Singleton)...)
+}
+
+func (c *Container) sharedSlot(key string) *sharedSlot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.shared == nil {
+		c.shared = make(map[string]*sharedSlot)
+	}
+	slot, ok := c.shared[key]
+	if !ok {
+		slot = &sharedSlot{}
+		c.shared[key] = slot
+	}
+	return slot
+}
@@ -0,0 +1,49 @@
+package autowired_test
+
+import (
+	"me.sithiramunasinghe/go-autowired"
+	"testing"
+)
+
+type sharedStore struct {
+	Name string
+}
+
+func (s *sharedStore) Greet() string {
+	return "hello from " + s.Name
+}
+
+// Test registrations of different types under one key share a single instance
+func TestRegisterShared(t *testing.T) {
+	container := autowired.NewContainer()
+
+	built := 0
+	newStore := func() *sharedStore {
+		built++
+		return &sharedStore{Name: "store"}
+	}
+
+	if err := autowired.RegisterShared[*sharedStore](container, "store", newStore); err != nil {
+		t.Fatalf("Failed to register shared store: %v", err)
+	}
+	if err := autowired.RegisterShared[Greeter](container, "store", newStore); err != nil {
+		t.Fatalf("Failed to register shared greeter: %v", err)
+	}
+
+	greeter, err := autowired.Resolve[Greeter](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve Greeter: %v", err)
+	}
+	store, err := autowired.Resolve[*sharedStore](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve store: %v", err)
+	}
+
+	if built != 1 || greeter != Greeter(store) {
+		t.Errorf("Expected one shared instance, built %d times", built)
+	}
+
+	if err := autowired.RegisterShared[*TestService](container, "store", newStore); err == nil {
+		t.Error("Expected error for a constructor not assignable to the registered type, got nil")
+	}
+}
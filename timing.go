@@ -0,0 +1,44 @@
+package autowired
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// TimingSample records one construction. Durations include the construction of dependencies built on the
+// way, so samples nest like the frames of a flame graph.
+type TimingSample struct {
+	Node     DependencyNode
+	Start    time.Time
+	Duration time.Duration
+	// Parent is the registration whose construction triggered this one. HasParent is false for roots.
+	Parent    DependencyNode
+	HasParent bool
+}
+
+type constructingKey struct{}
+
+// ExportTimings returns the constructions recorded while RecordTimings was set, ordered by start time
+func (c *Container) ExportTimings() []TimingSample {
+	c.timingsMu.Lock()
+	defer c.timingsMu.Unlock()
+
+	samples := append([]TimingSample(nil), c.timings...)
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Start.Before(samples[j].Start) })
+	return samples
+}
+
+// startTiming marks ctx as constructing info and returns a function recording the finished construction
+func (c *Container) startTiming(ctx context.Context, info *dependencyInfo) (context.Context, func()) {
+	sample := TimingSample{Node: info.node(), Start: time.Now()}
+	sample.Parent, sample.HasParent = ctx.Value(constructingKey{}).(DependencyNode)
+
+	return context.WithValue(ctx, constructingKey{}, sample.Node), func() {
+		sample.Duration = time.Since(sample.Start)
+
+		c.timingsMu.Lock()
+		defer c.timingsMu.Unlock()
+		c.timings = append(c.timings, sample)
+	}
+}
@@ -0,0 +1,43 @@
+package autowired_test
+
+import (
+	"context"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Test recorded timings link each construction to the one that triggered it
+func TestExportTimings(t *testing.T) {
+	container := autowired.NewContainer()
+	container.RecordTimings = true
+
+	_ = autowired.Register[TestService](container, func() *TestService {
+		time.Sleep(time.Millisecond)
+		return &TestService{}
+	})
+	_ = autowired.Register[Reporter](container, func(s *TestService) *Reporter {
+		return &Reporter{Primary: s}
+	})
+
+	if _, err := container.ResolveBatch(context.Background(), reflect.TypeOf(&Reporter{})); err != nil {
+		t.Fatalf("Failed to resolve batch: %v", err)
+	}
+
+	samples := container.ExportTimings()
+	if len(samples) != 2 {
+		t.Fatalf("Expected two samples, got %v", samples)
+	}
+
+	reporter, service := samples[0], samples[1]
+	if reporter.Node.Type != reflect.TypeOf(&Reporter{}) || reporter.HasParent {
+		t.Errorf("Expected Reporter to be the root sample, got %+v", reporter)
+	}
+	if service.Node.Type != reflect.TypeOf(&TestService{}) || !service.HasParent || service.Parent != reporter.Node {
+		t.Errorf("Expected TestService to be a child of Reporter, got %+v", service)
+	}
+	if service.Start.Before(reporter.Start) || service.Start.Add(service.Duration).After(reporter.Start.Add(reporter.Duration)) {
+		t.Errorf("Expected the TestService sample to nest within Reporter, got %+v and %+v", service, reporter)
+	}
+}
@@ -0,0 +1,108 @@
+package autowired
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Tx stages a batch of registrations that is applied to its container all at once on Commit, or not at all on
+// Rollback. Register into the staging container returned by Container with the usual helpers, and resolve from
+// the real container once the batch is committed.
+type Tx struct {
+	container *Container
+	staging   *Container
+	appended  map[reflect.Type]int
+	done      bool
+}
+
+// Begin starts a transaction on the container
+func (c *Container) Begin() *Tx {
+	staging := NewContainer()
+
+	c.appendMu.Lock()
+	appended := make(map[reflect.Type]int, len(c.appended))
+	for typ, names := range c.appended {
+		staging.appended[typ] = append([]string(nil), names...)
+		appended[typ] = len(names)
+	}
+	c.appendMu.Unlock()
+
+	c.mu.RLock()
+	staging.defaultScope = c.defaultScope
+	staging.shared = make(map[string]*sharedSlot, len(c.shared))
+	for key, slot := range c.shared {
+		staging.shared[key] = slot
+	}
+	c.mu.RUnlock()
+
+	return &Tx{container: c, staging: staging, appended: appended}
+}
+
+// Container returns the staging container the registrations of the transaction are made on
+func (tx *Tx) Container() *Container {
+	return tx.staging
+}
+
+// Commit applies every staged registration to the container, replacing registrations of the same type and
+// name. It fails without applying anything if the container has been sealed, the transaction has finished or
+// an embedded interface of a staged registration is already registered or bound to another registration.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return fmt.Errorf("transaction already finished")
+	}
+	c, staging := tx.container, tx.staging
+	staged := staging.registrations()
+
+	c.appendMu.Lock()
+	defer c.appendMu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isSealed() && len(staged) > 0 {
+		return &ErrSealed{Type: staged[0].typ}
+	}
+	for _, info := range staged {
+		if err := c.checkEmbeds(info); err != nil {
+			return err
+		}
+	}
+	tx.done = true
+
+	for _, info := range staged {
+		if _, exists := c.dependencies[info.typ]; !exists {
+			c.dependencies[info.typ] = make(map[string]*dependencyInfo)
+		}
+		c.dependencies[info.typ][info.name] = info
+	}
+	for typ := range staging.defaultable {
+		c.defaultable[typ] = true
+	}
+	for iface, impl := range staging.bindings {
+		if c.bindings == nil {
+			c.bindings = make(map[reflect.Type]DependencyNode)
+		}
+		c.bindings[iface] = impl
+	}
+	for key, slot := range staging.shared {
+		if c.shared == nil {
+			c.shared = make(map[string]*sharedSlot)
+		}
+		if _, exists := c.shared[key]; !exists {
+			c.shared[key] = slot
+		}
+	}
+	for typ, names := range staging.appended {
+		c.appended[typ] = append(c.appended[typ], names[tx.appended[typ]:]...)
+	}
+	return nil
+}
+
+// Rollback discards the staged registrations. It does nothing once the transaction has finished, so it can be
+// deferred right after Begin.
+func (tx *Tx) Rollback() {
+	if tx.done {
+		return
+	}
+	tx.done = true
+	tx.staging = NewContainer()
+}
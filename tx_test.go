@@ -0,0 +1,83 @@
+package autowired_test
+
+import (
+	"errors"
+	"io"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"testing"
+)
+
+// Test a failed batch leaves the container untouched after rollback, while a committed batch applies whole
+func TestTransaction(t *testing.T) {
+	container := autowired.NewContainer()
+	_ = autowired.Register[TestService](container, NewTestService)
+	before := container.RegisteredTypes()
+
+	tx := container.Begin()
+	if err := autowired.Register[RequestContext](tx.Container(), func() *RequestContext { return &RequestContext{} }); err != nil {
+		t.Fatalf("Failed to stage RequestContext: %v", err)
+	}
+	if err := autowired.Register[DiamondBase](tx.Container(), NewTestService); err == nil {
+		t.Fatal("Expected the mismatched constructor to fail, got nil")
+	}
+	tx.Rollback()
+
+	if after := container.RegisteredTypes(); !reflect.DeepEqual(after, before) {
+		t.Errorf("Expected the container to be unchanged after rollback, got %v", after)
+	}
+	if err := tx.Commit(); err == nil {
+		t.Error("Expected committing a rolled back transaction to fail, got nil")
+	}
+
+	tx = container.Begin()
+	defer tx.Rollback()
+	_ = autowired.Register[RequestContext](tx.Container(), func() *RequestContext { return &RequestContext{ID: 1} })
+	_ = autowired.Register[DiamondBase](tx.Container(), func() *DiamondBase { return &DiamondBase{} })
+
+	if _, err := autowired.Resolve[*RequestContext](container); err == nil {
+		t.Error("Staged registrations should not be visible before commit")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	if nodes := container.RegisteredTypes(); len(nodes) != 3 {
+		t.Errorf("Expected both staged registrations to be applied, got %v", nodes)
+	}
+	if request, err := autowired.Resolve[*RequestContext](container); err != nil || request.ID != 1 {
+		t.Errorf("Expected the committed RequestContext, got %v (%v)", request, err)
+	}
+}
+
+// Test a batch binding an embedded interface that is already bound fails to commit without applying anything
+func TestTransactionBindingConflict(t *testing.T) {
+	container := autowired.NewContainer()
+	_ = autowired.RegisterWithEmbedded[EmbeddedResource](container, func() *EmbeddedResource {
+		return &EmbeddedResource{Name: "resource"}
+	}, "resource")
+	before := container.RegisteredTypes()
+
+	tx := container.Begin()
+	defer tx.Rollback()
+	_ = autowired.Register[RequestContext](tx.Container(), func() *RequestContext { return &RequestContext{} })
+	if err := autowired.RegisterWithEmbedded[EmbeddedResource](tx.Container(), func() *EmbeddedResource {
+		return &EmbeddedResource{Name: "other"}
+	}, "other"); err != nil {
+		t.Fatalf("Failed to stage EmbeddedResource: %v", err)
+	}
+
+	if err := tx.Commit(); !errors.Is(err, &autowired.ErrAlreadyRegistered{}) {
+		t.Fatalf("Expected ErrAlreadyRegistered for a second io.Closer, got %v", err)
+	}
+	if after := container.RegisteredTypes(); !reflect.DeepEqual(after, before) {
+		t.Errorf("Expected the container to be unchanged after a failed commit, got %v", after)
+	}
+	closer, err := autowired.Resolve[io.Closer](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve io.Closer: %v", err)
+	}
+	if closer.(*EmbeddedResource).Name != "resource" {
+		t.Errorf("Expected io.Closer to stay bound to the first registration, got %v", closer)
+	}
+}
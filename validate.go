@@ -0,0 +1,224 @@
+package autowired
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RegisterValidator registers a validator run on autowired fields of typ that carry a validate tag
+func (c *Container) RegisterValidator(typ reflect.Type, validator func(interface{}) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.validators[typ] = validator
+}
+
+// validateField checks an injected field against the comma-separated rules of its validate tag
+// and the validator registered for its type
+func (c *Container) validateField(field reflect.Value, rules string) error {
+	for _, rule := range strings.Split(rules, ",") {
+		switch strings.TrimSpace(rule) {
+		case "":
+		case "nonnil":
+			if isNillable(field.Kind()) && field.IsNil() {
+				return fmt.Errorf("required dependency of type %v is nil", field.Type())
+			}
+		default:
+			return fmt.Errorf("unknown validation rule '%s'", rule)
+		}
+	}
+
+	c.mu.RLock()
+	validator := c.validators[field.Type()]
+	c.mu.RUnlock()
+
+	if validator != nil {
+		return validator(field.Interface())
+	}
+	return nil
+}
+
+// Validate checks every registration without constructing anything. It reports constructors that do not
+// return (T) or (T, error), constructor parameters that cannot be resolved, declared dependencies that are
+// not registered, and dependency cycles. All problems are returned together.
+func (c *Container) Validate() error {
+	if c.WarnDangling {
+		c.warnDangling()
+	}
+
+	var errs []error
+	for _, info := range c.registrations() {
+		if declared, ok := info.declared.Load().([]DependencyNode); ok {
+			for _, dep := range declared {
+				if err := c.checkDependency(dep.Type, dep.Name); err != nil {
+					errs = append(errs, fmt.Errorf("%v: declared dependency %v: %w", info.node(), dep, err))
+				}
+			}
+		}
+		if !info.constructor.IsValid() {
+			continue
+		}
+
+		constructorType := info.constructor.Type()
+		switch {
+		case constructorType.NumOut() == 0 || constructorType.NumOut() > 2:
+			errs = append(errs, fmt.Errorf("%v: constructor returns %d values, want (T) or (T, error)", info.node(), constructorType.NumOut()))
+		case constructorType.NumOut() == 2 && !constructorType.Out(1).Implements(errorType):
+			errs = append(errs, fmt.Errorf("%v: second constructor result %v is not an error", info.node(), constructorType.Out(1)))
+		}
+
+		for i := 0; i < constructorType.NumIn(); i++ {
+			if err := c.checkParam(info, i, constructorType.In(i)); err != nil {
+				errs = append(errs, fmt.Errorf("%v: parameter %d of type %v: %w", info.node(), i, constructorType.In(i), err))
+			}
+		}
+	}
+
+	return joinErrors(append(errs, c.cycles()...))
+}
+
+// ValidateLifetimes reports every registration that depends on a shorter-lived one without constructing
+// anything: singletons depending on request-scoped or prototype registrations, and request-scoped registrations
+// depending on prototypes. The longer-lived registration keeps the shorter-lived instance captive for its own
+// lifetime. Each violation is an ErrCaptiveDependency, and all are returned together.
+func (c *Container) ValidateLifetimes() error {
+	var errs []error
+	for _, info := range c.registrations() {
+		for _, dep := range c.dependenciesOf(info) {
+			target, _, err := c.lookup(dep.Type, dep.Name)
+			if err != nil {
+				continue
+			}
+			if lifetimeRank(target.scope) < lifetimeRank(info.scope) {
+				errs = append(errs, &ErrCaptiveDependency{Singleton: info.node(), Dependency: target.node(), Scope: target.scope, HolderScope: info.scope})
+			}
+		}
+	}
+	return joinErrors(errs)
+}
+
+// lifetimeRank orders scopes from the shortest-lived instance to the longest-lived one
+func lifetimeRank(scope Scope) int {
+	switch scope {
+	case Prototype:
+		return 0
+	case Request:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// checkParam reports whether the constructor parameter at index of info can be resolved
+func (c *Container) checkParam(info *dependencyInfo, index int, paramType reflect.Type) error {
+	if paramType == contextType || paramType == resolutionNameType || isRegistry(paramType) {
+		return nil
+	}
+	if isProvider(paramType) {
+		return c.checkDependency(paramType.Out(0), "")
+	}
+	if isParamObject(paramType) {
+		for _, field := range paramFields(paramType) {
+			if err := c.checkDependency(field.Type, field.name); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+		return nil
+	}
+	if names, ok := info.collection(index); ok {
+		for _, name := range names {
+			if err := c.checkDependency(paramType.Elem(), name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	c.mu.RLock()
+	_, err := c.getDependencyInfo(paramType, info.qualifier(index))
+	c.mu.RUnlock()
+	hasLoggerFactory := c.loggerFactoryFunc() != nil
+
+	if err == nil || c.canDefault(paramType, err) || (paramType == loggerType && hasLoggerFactory) {
+		return nil
+	}
+	return err
+}
+
+// warnDangling logs the constructor parameters of types nothing is registered for, once per missing type
+func (c *Container) warnDangling() {
+	for _, info := range c.registrations() {
+		if !info.constructor.IsValid() {
+			continue
+		}
+
+		constructorType := info.constructor.Type()
+		for i := 0; i < constructorType.NumIn(); i++ {
+			paramType := constructorType.In(i)
+			if err := c.checkParam(info, i, paramType); !errors.Is(err, &ErrNotRegistered{}) {
+				continue
+			}
+			if _, warned := c.dangling.LoadOrStore(paramType, true); !warned {
+				c.logger().Warnf("%v depends on %v, which is not registered", info.node(), paramType)
+			}
+		}
+	}
+}
+
+// checkDependency reports whether typ is registered under name
+func (c *Container) checkDependency(typ reflect.Type, name string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, err := c.getDependencyInfo(typ, name)
+	return err
+}
+
+// cycles returns an ErrCircularDependency for every cycle between constructor registrations
+func (c *Container) cycles() []error {
+	graph := c.graph()
+	nodes := c.RegisteredTypes()
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[DependencyNode]int)
+	var stack []DependencyNode
+	var errs []error
+
+	var visit func(node DependencyNode)
+	visit = func(node DependencyNode) {
+		state[node] = visiting
+		stack = append(stack, node)
+		for _, dep := range graph[node] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				var path []reflect.Type
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						for _, n := range stack[i:] {
+							path = append(path, n.Type)
+						}
+						break
+					}
+				}
+				errs = append(errs, &ErrCircularDependency{Path: append(path, dep.Type)})
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = visited
+	}
+
+	for _, node := range nodes {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,156 @@
+package autowired_test
+
+import (
+	"errors"
+	"me.sithiramunasinghe/go-autowired"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test nonnil validation reports a nil injected dependency
+func TestAutoWireValidateNonNil(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, func() *TestService { return nil })
+
+	type App struct {
+		Service *TestService `autowire:"" validate:"nonnil"`
+	}
+
+	err := autowired.AutoWire(container, &App{})
+	if err == nil || !strings.Contains(err.Error(), "Service") {
+		t.Errorf("Expected validation error naming field Service, got %v", err)
+	}
+
+	type LenientApp struct {
+		Service *TestService `autowire:""`
+	}
+
+	if err := autowired.AutoWire(container, &LenientApp{}); err != nil {
+		t.Errorf("Fields without a validate tag should accept nil, got %v", err)
+	}
+}
+
+// Test custom validators registered per type
+func TestAutoWireCustomValidator(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, func() *TestService { return &TestService{} })
+
+	invalid := errors.New("value must be set")
+	container.RegisterValidator(reflect.TypeOf(&TestService{}), func(v interface{}) error {
+		if v.(*TestService).Value == "" {
+			return invalid
+		}
+		return nil
+	})
+
+	type App struct {
+		Service *TestService `autowire:"" validate:""`
+	}
+
+	if err := autowired.AutoWire(container, &App{}); !errors.Is(err, invalid) {
+		t.Errorf("Expected custom validation error, got %v", err)
+	}
+}
+
+// Test Validate reports bad constructor shapes, unresolvable parameters and cycles together
+func TestValidate(t *testing.T) {
+	container := autowired.NewContainer()
+	_ = autowired.Register[TestService](container, NewTestService)
+	autowired.RegisterDefaultable[*OptionalConfig](container)
+	_ = autowired.Register[Reporter](container, func(s *TestService, cfg *OptionalConfig) *Reporter {
+		return &Reporter{Primary: s}
+	})
+
+	if err := container.Validate(); err != nil {
+		t.Fatalf("Expected a valid container, got %v", err)
+	}
+
+	err := container.Register(func() (*RequestContext, error, int) {
+		return &RequestContext{}, nil, 0
+	})
+	if err != nil {
+		t.Fatalf("Failed to register RequestContext: %v", err)
+	}
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft { return &DiamondLeft{Base: b} })
+	_ = autowired.Register[ServiceA](container, func(b *ServiceB) *ServiceA { return &ServiceA{B: b} })
+	_ = autowired.Register[ServiceB](container, func(a *ServiceA) *ServiceB { return &ServiceB{A: a} })
+
+	err = container.Validate()
+	var multi *autowired.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 3 {
+		t.Fatalf("Expected three problems, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "*autowired_test.RequestContext (requestContext): constructor returns 3 values") {
+		t.Errorf("Expected the constructor shape to be reported, got %v", err)
+	}
+	if !errors.Is(err, &autowired.ErrNotRegistered{}) || !strings.Contains(err.Error(), "DiamondLeft (diamondLeft): parameter 0") {
+		t.Errorf("Expected the missing DiamondBase to be reported, got %v", err)
+	}
+
+	var cycle *autowired.ErrCircularDependency
+	if !errors.As(err, &cycle) {
+		t.Fatalf("Expected the cycle to be reported, got %v", err)
+	}
+	expected := []reflect.Type{reflect.TypeOf(&ServiceA{}), reflect.TypeOf(&ServiceB{}), reflect.TypeOf(&ServiceA{})}
+	if !reflect.DeepEqual(cycle.Path, expected) {
+		t.Errorf("Expected cycle %v, got %v", expected, cycle.Path)
+	}
+}
+
+// Test every dependency on a shorter-lived registration is reported
+func TestValidateLifetimes(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[DiamondBase](container, func() *DiamondBase { return &DiamondBase{} }, autowired.Prototype)
+	_ = autowired.Register[DiamondLeft](container, func(b *DiamondBase) *DiamondLeft { return &DiamondLeft{Base: b} }, autowired.Request)
+	_ = autowired.Register[DiamondRight](container, func(b *DiamondBase) *DiamondRight { return &DiamondRight{Base: b} }, autowired.Prototype)
+	_ = autowired.Register[DiamondTop](container, func(l *DiamondLeft, r *DiamondRight) *DiamondTop {
+		return &DiamondTop{Left: l, Right: r}
+	})
+
+	err := container.ValidateLifetimes()
+	var multi *autowired.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 3 {
+		t.Fatalf("Expected three captive dependencies, got %v", err)
+	}
+
+	for _, expected := range []string{
+		"singleton *autowired_test.DiamondTop (diamondTop) depends on request *autowired_test.DiamondLeft (diamondLeft)",
+		"singleton *autowired_test.DiamondTop (diamondTop) depends on prototype *autowired_test.DiamondRight (diamondRight)",
+		"request *autowired_test.DiamondLeft (diamondLeft) depends on prototype *autowired_test.DiamondBase (diamondBase)",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected '%s' to be reported, got %v", expected, err)
+		}
+	}
+	if !errors.Is(err, &autowired.ErrCaptiveDependency{}) {
+		t.Errorf("Expected ErrCaptiveDependency, got %v", err)
+	}
+
+	registerDiamond(container)
+	if err := container.ValidateLifetimes(); err != nil {
+		t.Errorf("Expected singletons throughout to be valid, got %v", err)
+	}
+}
+
+// Test a dependency on a shorter-lived registration through an interface is reported
+func TestValidateLifetimesInterface(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[GraphDB](container, func() *GraphDB { return &GraphDB{} }, autowired.Prototype)
+	_ = autowired.Register[GraphServer](container, func(s GraphStore) *GraphServer {
+		return &GraphServer{Store: s}
+	})
+
+	err := container.ValidateLifetimes()
+	if !errors.Is(err, &autowired.ErrCaptiveDependency{}) {
+		t.Fatalf("Expected ErrCaptiveDependency, got %v", err)
+	}
+	expected := "singleton *autowired_test.GraphServer (graphServer) depends on prototype *autowired_test.GraphDB (graphDB)"
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("Expected '%s' to be reported, got %v", expected, err)
+	}
+}
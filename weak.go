@@ -0,0 +1,27 @@
+package autowired
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// weakOption makes a singleton cache its instance behind a weak reference
+type weakOption func(instance interface{}) func() (interface{}, bool)
+
+// RegisterWeakSingleton registers a singleton that the container references weakly: once nothing else holds
+// the instance, the garbage collector may reclaim it and the next resolution constructs a new one. The
+// constructor must return *T. Collected instances never see OnDestroy. Toolchains before Go 1.24 have no weak
+// references, so there the instance is held strongly like any other singleton.
+func RegisterWeakSingleton[T any](c *Container, constructor interface{}, options ...interface{}) error {
+	constructorType := reflect.TypeOf(constructor)
+	if constructorType == nil || constructorType.Kind() != reflect.Func || constructorType.NumOut() == 0 ||
+		constructorType.Out(0) != reflect.TypeOf((*T)(nil)) {
+		return &ErrInvalidConstructor{Reason: fmt.Sprintf("weak singleton constructor must return %v", reflect.TypeOf((*T)(nil)))}
+	}
+
+	ref := func(instance interface{}) func() (interface{}, bool) {
+		p, _ := instance.(*T)
+		return weakRef(p)
+	}
+	return registerTyped[T](c, constructor, append(options, Singleton, weakOption(ref))...)
+}
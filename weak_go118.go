@@ -0,0 +1,10 @@
+//go:build !go1.24
+
+package autowired
+
+// weakRef holds p strongly, since weak references need Go 1.24
+func weakRef[T any](p *T) func() (interface{}, bool) {
+	return func() (interface{}, bool) {
+		return p, true
+	}
+}
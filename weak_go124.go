@@ -0,0 +1,16 @@
+//go:build go1.24
+
+package autowired
+
+import "weak"
+
+// weakRef references p without keeping it alive
+func weakRef[T any](p *T) func() (interface{}, bool) {
+	w := weak.Make(p)
+	return func() (interface{}, bool) {
+		if v := w.Value(); v != nil {
+			return v, true
+		}
+		return nil, false
+	}
+}
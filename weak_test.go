@@ -0,0 +1,59 @@
+package autowired_test
+
+import (
+	"me.sithiramunasinghe/go-autowired"
+	"runtime"
+	"testing"
+)
+
+type WeakCache struct {
+	Entries map[string]string
+}
+
+// Test a weak singleton is kept while referenced and rebuilt once collected.
+// Collection is up to the garbage collector, so the rebuild is checked on a best-effort basis.
+func TestRegisterWeakSingleton(t *testing.T) {
+	container := autowired.NewContainer()
+
+	built := 0
+	err := autowired.RegisterWeakSingleton[WeakCache](container, func() *WeakCache {
+		built++
+		return &WeakCache{Entries: map[string]string{}}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register weak singleton: %v", err)
+	}
+
+	held, err := autowired.Resolve[*WeakCache](container)
+	if err != nil {
+		t.Fatalf("Failed to resolve WeakCache: %v", err)
+	}
+	runtime.GC()
+
+	again, _ := autowired.Resolve[*WeakCache](container)
+	if again != held || built != 1 {
+		t.Fatalf("Expected a referenced weak singleton to be reused, built %d times", built)
+	}
+	runtime.KeepAlive(held)
+
+	for i := 0; i < 5 && built == 1; i++ {
+		runtime.GC()
+		_, _ = autowired.Resolve[*WeakCache](container)
+	}
+	if built == 1 {
+		t.Skip("WeakCache was not collected; weak references are unavailable or the collector kept it")
+	}
+	if built != 2 {
+		t.Errorf("Expected one reconstruction after collection, built %d times", built)
+	}
+}
+
+// Test a weak singleton must be constructed as a pointer
+func TestRegisterWeakSingletonRequiresPointer(t *testing.T) {
+	container := autowired.NewContainer()
+
+	err := autowired.RegisterWeakSingleton[WeakCache](container, func() WeakCache { return WeakCache{} })
+	if err == nil {
+		t.Error("Expected error for a weak singleton constructor not returning a pointer, got nil")
+	}
+}
@@ -0,0 +1,81 @@
+package autowired
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// GenerateWiringCode renders Go source for a function that recreates the registrations of the container with
+// their type, name and scope. Options such as hooks and qualifiers are not reproduced. Registrations whose
+// constructor has no name, such as closures, method values and factories, are emitted as commented placeholders.
+func (c *Container) GenerateWiringCode() string {
+	var b strings.Builder
+	b.WriteString("func Wire(c *autowired.Container) error {\n")
+	for _, info := range c.registrations() {
+		target := info.typ
+		if target.Kind() == reflect.Ptr {
+			target = target.Elem()
+		}
+
+		constructor, ok := info.constructorName(target)
+		if !ok {
+			fmt.Fprintf(&b, "\t// %v: constructor cannot be referenced by name\n", info.node())
+			fmt.Fprintf(&b, "\t// autowired.Register[%v](c, nil, %q, %s)\n", target, info.name, scopeCode(info.scope))
+			continue
+		}
+
+		fmt.Fprintf(&b, "\tif err := autowired.Register[%v](c, %s, %q, %s); err != nil {\n", target, constructor, info.name, scopeCode(info.scope))
+		b.WriteString("\t\treturn err\n\t}\n")
+	}
+	b.WriteString("\treturn nil\n}\n")
+	return b.String()
+}
+
+// constructorName returns the package-qualified name of the registration's constructor, if it is a top-level
+// function. target is the registered type, whose package name qualifies constructors from the same package.
+func (info *dependencyInfo) constructorName(target reflect.Type) (string, bool) {
+	if !info.constructor.IsValid() {
+		return "", false
+	}
+	fn := runtime.FuncForPC(info.constructor.Pointer())
+	if fn == nil {
+		return "", false
+	}
+
+	// Function names are the import path followed by the identifier, e.g. "example.com/app/db.NewPool"
+	full := fn.Name()
+	slash := strings.LastIndex(full, "/")
+	dot := strings.Index(full[slash+1:], ".")
+	if dot < 0 {
+		return "", false
+	}
+	pkgPath, ident := full[:slash+1+dot], full[slash+2+dot:]
+	if strings.ContainsAny(ident, ".()-") {
+		return "", false
+	}
+
+	// The last path element is not always the package name, so prefer the one the type reports
+	pkg := path.Base(pkgPath)
+	if target.PkgPath() == pkgPath {
+		if qualified := target.String(); strings.Contains(qualified, ".") {
+			pkg = qualified[:strings.Index(qualified, ".")]
+		}
+	}
+	return pkg + "." + ident, true
+}
+
+func scopeCode(scope Scope) string {
+	switch scope {
+	case Singleton:
+		return "autowired.Singleton"
+	case Prototype:
+		return "autowired.Prototype"
+	case Request:
+		return "autowired.Request"
+	default:
+		return fmt.Sprintf("autowired.Scope(%d)", int(scope))
+	}
+}
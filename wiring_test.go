@@ -0,0 +1,30 @@
+package autowired_test
+
+import (
+	"me.sithiramunasinghe/go-autowired"
+	"strings"
+	"testing"
+)
+
+// Test generated wiring code names constructors and marks closures as placeholders
+func TestGenerateWiringCode(t *testing.T) {
+	container := autowired.NewContainer()
+
+	_ = autowired.Register[TestService](container, NewTestService)
+	_ = autowired.Register[TestService](container, NewTestService, "perCall", autowired.Prototype)
+	_ = autowired.Register[RequestContext](container, func() *RequestContext {
+		return &RequestContext{}
+	}, autowired.Request)
+
+	code := container.GenerateWiringCode()
+
+	for _, line := range []string{
+		`if err := autowired.Register[autowired_test.TestService](c, autowired_test.NewTestService, "testService", autowired.Singleton); err != nil {`,
+		`if err := autowired.Register[autowired_test.TestService](c, autowired_test.NewTestService, "perCall", autowired.Prototype); err != nil {`,
+		`// autowired.Register[autowired_test.RequestContext](c, nil, "requestContext", autowired.Request)`,
+	} {
+		if !strings.Contains(code, line) {
+			t.Errorf("Expected generated code to contain %q, got:\n%s", line, code)
+		}
+	}
+}